@@ -0,0 +1,47 @@
+package xtoken
+
+import (
+	"encoding/base64"
+)
+
+const (
+	base64EncodedLen = 16 // base64 encoded len
+
+	// base64Encoding is the alphabet of base64.RawURLEncoding.
+	base64Encoding = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// base64Dec is the decoding map for base64Encoding.
+var base64Dec [256]byte
+
+func init() {
+	for i := 0; i < len(base64Dec); i++ {
+		base64Dec[i] = 0xFF
+	}
+	for i := 0; i < len(base64Encoding); i++ {
+		base64Dec[base64Encoding[i]] = byte(i)
+	}
+}
+
+// Base64 returns the 16 chars base64.RawURLEncoding representation of the token.
+func (token Token) Base64() string {
+	return base64.RawURLEncoding.EncodeToString(token[:])
+}
+
+// FromBase64 reads a Token from its 16 chars base64.RawURLEncoding
+// representation, the standard alphabet and padding aren't accepted.
+func FromBase64(s string) (Token, error) {
+	var token Token
+	if err := checkText(s, base64EncodedLen, &base64Dec); err != nil {
+		return token, err
+	}
+	// 12 bytes are exactly 16 chars, there are no trailing bits to check.
+	for i, j := 0, 0; i < rawLen; i, j = i+3, j+4 {
+		v := uint32(base64Dec[s[j]])<<18 | uint32(base64Dec[s[j+1]])<<12 |
+			uint32(base64Dec[s[j+2]])<<6 | uint32(base64Dec[s[j+3]])
+		token[i] = byte(v >> 16)
+		token[i+1] = byte(v >> 8)
+		token[i+2] = byte(v)
+	}
+	return token, nil
+}
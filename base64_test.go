@@ -0,0 +1,113 @@
+package xtoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBase64(t *testing.T) {
+	for _, v := range IDs {
+		s := v.token.Base64()
+		if len(s) != base64EncodedLen {
+			t.Errorf("len(Base64()) = %d, want %d", len(s), base64EncodedLen)
+		}
+		got, err := FromBase64(s)
+		if err != nil {
+			t.Fatalf("FromBase64(%q) err: %v", s, err)
+		}
+		if got != v.token {
+			t.Errorf("FromBase64(%q) = %x, want %x", s, got[:], v.token[:])
+		}
+	}
+	tests := []struct {
+		token Token
+		want  string
+	}{
+		{IDs[0].token, "TYjhW2D0huQoQS3J"},
+		{nilToken, "AAAAAAAAAAAAAAAA"},
+		{Token{0xfb, 0xff, 0xbf, 0xfb, 0xff, 0xbf, 0xfb, 0xff, 0xbf, 0xfb, 0xff, 0xbf}, "-_-_-_-_-_-_-_-_"},
+	}
+	for _, tt := range tests {
+		if got := tt.token.Base64(); got != tt.want {
+			t.Errorf("Base64() = %q, want %q", got, tt.want)
+		}
+		if got, err := FromBase64(tt.want); err != nil || got != tt.token {
+			t.Errorf("FromBase64(%q) = %x, %v, want %x", tt.want, got[:], err, tt.token[:])
+		}
+	}
+}
+
+func TestFromBase64Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"TYjhW2D0huQoQS3",
+		"TYjhW2D0huQoQS3JA",
+		"TYjhW2D0huQoQS3J==",
+		"TYjhW2D0huQoQS+J", // standard alphabet
+		"TYjhW2D0huQoQS/J",
+		"TYjhW2D0huQoQS=J",
+	} {
+		if _, err := FromBase64(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromBase64(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+}
+
+// parsers are the parsers of every string form of a Token, with the matching
+// encoder.
+var parsers = []struct {
+	name   string
+	parse  func(string) (Token, error)
+	format func(Token) string
+}{
+	{"String", FromString, Token.String},
+	{"Hex", FromHex, Token.Hex},
+	{"Base64", FromBase64, Token.Base64},
+}
+
+func FuzzParsers(f *testing.F) {
+	for _, v := range IDs {
+		f.Add(v.token.String())
+		f.Add(v.token.Hex())
+		f.Add(v.token.Base64())
+	}
+	f.Add(strings.ToUpper(IDs[0].token.Hex()))
+	f.Add("________________________________")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, s string) {
+		accepted := 0
+		for _, p := range parsers {
+			token, err := p.parse(s)
+			if err != nil {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("%s: parse(%q) err = %v, want %v", p.name, s, err, ErrInvalidToken)
+				}
+				continue
+			}
+			accepted++
+			// Re-encoding must give back the same token.
+			if got, err := p.parse(p.format(token)); err != nil || got != token {
+				t.Fatalf("%s: parse(format(%x)) = %x, %v", p.name, token[:], got[:], err)
+			}
+		}
+		if accepted > 1 {
+			t.Fatalf("%q is accepted by %d parsers", s, accepted)
+		}
+
+		// The output of one format must not be accepted by another one.
+		var token Token
+		copy(token[:], s)
+		for _, p := range parsers {
+			out := p.format(token)
+			for _, q := range parsers {
+				if q.name == p.name {
+					continue
+				}
+				if _, err := q.parse(out); err == nil {
+					t.Fatalf("%s accepts the %s output %q", q.name, p.name, out)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package xtoken
+
+import "strings"
+
+// maxBatchCounter bounds the counter range reserved at once by NewBatch, so
+// that a reservation always fits in the counter space of one second.
+const maxBatchCounter = 1 << 23
+
+// NewBatch generates n globally unique tokens, see Generator.NewBatch.
+func NewBatch(n int) []Token {
+	return defaultGenerator.NewBatch(n)
+}
+
+// FillBatch fills tokens with globally unique tokens, see Generator.FillBatch.
+func FillBatch(tokens []Token) {
+	defaultGenerator.FillBatch(tokens)
+}
+
+// NewBatchStrings generates n globally unique tokens and returns their string
+// representations, see Generator.NewBatchStrings.
+func NewBatchStrings(n int) []string {
+	return defaultGenerator.NewBatchStrings(n)
+}
+
+// NewBatch generates n globally unique tokens, in increasing counter order.
+// It reads the clock and reserves a range of the counter once for the whole
+// batch, which is much cheaper than calling New n times. Large batches are
+// split so that a reservation never wraps the counter within one second.
+// Like New, it blocks for as long as the clock of the Generator doesn't reach
+// the next second when it has to wait for it.
+func (g *Generator) NewBatch(n int) []Token {
+	if n <= 0 {
+		return nil
+	}
+	tokens := make([]Token, n)
+	g.FillBatch(tokens)
+	return tokens
+}
+
+// FillBatch is like NewBatch but fills the caller's slice instead of allocating
+// one, e.g. to reuse a buffer across batches.
+func (g *Generator) FillBatch(tokens []Token) {
+	if len(tokens) == 0 {
+		return
+	}
+	generated(len(tokens))
+	var steps []uint32
+	maxStep := 1
+	if g.maxStep > 1 {
+		steps = make([]uint32, min(len(tokens), int(maxBatchCounter/g.maxStep)))
+		maxStep = int(g.maxStep)
+	}
+	for chunk := tokens; len(chunk) > 0; {
+		size := len(chunk)
+		if size > maxBatchCounter/maxStep {
+			size = maxBatchCounter / maxStep
+		}
+		if g.limit > 0 && size > int(g.limit) {
+			size = int(g.limit)
+		}
+		// Draw the steps first to reserve their sum.
+		total := uint32(size)
+		if g.maxStep > 1 {
+			total = 0
+			for i := 0; i < size; i++ {
+				steps[i] = g.step()
+				total += steps[i]
+			}
+		}
+		secs, last, err := g.reserve(uint32(size), total, OverflowWait)
+		for err != nil {
+			// The only error is ErrClockStalled, keep waiting for the clock.
+			secs, last, err = g.reserve(uint32(size), total, OverflowWait)
+		}
+		i := last - total
+		for j := 0; j < size; j++ {
+			if g.maxStep > 1 {
+				i += steps[j]
+			} else {
+				i++
+			}
+			chunk[j] = g.newToken(secs, i)
+		}
+		chunk = chunk[size:]
+	}
+}
+
+// NewBatchStrings is like NewBatch but returns the string representations of
+// the tokens. They are encoded into a single buffer, so the returned strings
+// share their memory.
+func (g *Generator) NewBatchStrings(n int) []string {
+	tokens := g.NewBatch(n)
+	if tokens == nil {
+		return nil
+	}
+	var b strings.Builder
+	b.Grow(len(tokens) * encodedLen)
+	var text [encodedLen]byte
+	for _, token := range tokens {
+		StdEncoding.encode(text[:], token[:])
+		b.Write(text[:])
+	}
+	buf := b.String()
+	s := make([]string, len(tokens))
+	for i := range s {
+		s[i] = buf[i*encodedLen : (i+1)*encodedLen]
+	}
+	return s
+}
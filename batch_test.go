@@ -0,0 +1,184 @@
+package xtoken
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewBatch(t *testing.T) {
+	if got := NewBatch(0); got != nil {
+		t.Errorf("NewBatch(0) = %v, want nil", got)
+	}
+	n := 1000000
+	if testing.Short() {
+		n = 100000
+	}
+	// Keep the 3-byte counter away from its byte-wise wrap for the order check.
+	atomic.StoreUint32(&objectIDCounter, 0)
+	tokens := NewBatch(n)
+	if len(tokens) != n {
+		t.Fatalf("len(NewBatch()) = %d, want %d", len(tokens), n)
+	}
+	seen := make(map[Token]struct{}, n)
+	for i, token := range tokens {
+		if _, ok := seen[token]; ok {
+			t.Fatalf("NewBatch() generated a duplicate at %d", i)
+		}
+		seen[token] = struct{}{}
+		if i > 0 && !tokens[i-1].Less(token) {
+			t.Fatalf("NewBatch() tokens %d and %d are not ordered", i-1, i)
+		}
+	}
+	if token := New(); token.Compare(tokens[n-1]) <= 0 {
+		t.Errorf("New() after NewBatch() reused the counter range")
+	}
+}
+
+func TestFillBatch(t *testing.T) {
+	FillBatch(nil)
+	tokens := make([]Token, 100)
+	FillBatch(tokens)
+	seen := make(map[Token]struct{})
+	for i, token := range tokens {
+		if token.IsZero() {
+			t.Fatalf("FillBatch() left token %d unset", i)
+		}
+		if _, ok := seen[token]; ok {
+			t.Fatalf("FillBatch() generated a duplicate at %d", i)
+		}
+		seen[token] = struct{}{}
+	}
+	if n := testing.AllocsPerRun(10, func() { FillBatch(tokens) }); n != 0 {
+		t.Errorf("FillBatch() allocs = %v, want 0", n)
+	}
+}
+
+func TestNewBatchCounterWrap(t *testing.T) {
+	// Reserve a range crossing the 3-byte boundary.
+	atomic.StoreUint32(&objectIDCounter, 1<<24-10)
+	tokens := NewBatch(20)
+	seen := make(map[Token]struct{})
+	for i, token := range tokens {
+		if _, ok := seen[token]; ok {
+			t.Fatalf("NewBatch() generated a duplicate at %d", i)
+		}
+		seen[token] = struct{}{}
+		if i > 0 && (token.Counter()-tokens[i-1].Counter())&0xFFFFFF != 1 {
+			t.Errorf("NewBatch() counter delta at %d != 1", i)
+		}
+	}
+}
+
+func TestNewBatchRandomCounterStep(t *testing.T) {
+	g := NewGenerator(WithRandomCounterStep(8))
+	tokens := g.NewBatch(10000)
+	seen := make(map[Token]struct{})
+	for i, token := range tokens {
+		if _, ok := seen[token]; ok {
+			t.Fatalf("NewBatch() generated a duplicate at %d", i)
+		}
+		seen[token] = struct{}{}
+		if i > 0 && token.Time().Equal(tokens[i-1].Time()) {
+			if delta := (token.Counter() - tokens[i-1].Counter()) & 0xFFFFFF; delta < 1 || delta > 8 {
+				t.Fatalf("NewBatch() counter delta = %d, want within [1, 8]", delta)
+			}
+		}
+	}
+}
+
+func TestNewBatchThenNewStrict(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithCounter(0), WithClock(clock.Now))
+	seen := make([]bool, 1<<24)
+	for _, token := range g.NewBatch(1 << 23) {
+		seen[token.Counter()] = true
+	}
+	// The batch opened the window at its first counter value, so NewStrict
+	// fails before repeating any of them.
+	for n := 0; ; n++ {
+		token, err := g.NewStrict()
+		if err == ErrCounterOverflow {
+			if n != 1<<23 {
+				t.Errorf("NewStrict() failed after %d tokens, want %d", n, 1<<23)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("NewStrict() err: %v", err)
+		}
+		if seen[token.Counter()] {
+			t.Fatalf("NewStrict() #%d repeated counter %d", n, token.Counter())
+		}
+		seen[token.Counter()] = true
+	}
+}
+
+func TestNewBatchStrings(t *testing.T) {
+	s := NewBatchStrings(1000)
+	if len(s) != 1000 {
+		t.Fatalf("len(NewBatchStrings()) = %d, want 1000", len(s))
+	}
+	seen := make(map[Token]struct{})
+	for _, str := range s {
+		token, err := FromString(str)
+		if err != nil {
+			t.Fatalf("FromString(%q) err: %v", str, err)
+		}
+		if _, ok := seen[token]; ok {
+			t.Fatalf("NewBatchStrings() generated a duplicate")
+		}
+		seen[token] = struct{}{}
+	}
+}
+
+func BenchmarkNewBatch(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewBatch(1000)
+	}
+}
+
+func BenchmarkFillBatch(b *testing.B) {
+	tokens := make([]Token, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FillBatch(tokens)
+	}
+}
+
+func BenchmarkFillBatchRandomCounterStep(b *testing.B) {
+	g := NewGenerator(WithRandomCounterStep(8))
+	tokens := make([]Token, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.FillBatch(tokens)
+	}
+}
+
+func BenchmarkNewLoop(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tokens := make([]Token, 1000)
+		for j := range tokens {
+			tokens[j] = New()
+		}
+	}
+}
+
+func BenchmarkNewBatchStrings(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewBatchStrings(1000)
+	}
+}
+
+func BenchmarkNewStringLoop(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := make([]string, 1000)
+		for j := range s {
+			s[j] = New().String()
+		}
+	}
+}
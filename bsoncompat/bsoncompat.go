@@ -0,0 +1,81 @@
+// Package bsoncompat adapts xtoken.Token to the v1 mongo-go-driver.
+//
+// xtoken.Token implements the bson.ValueMarshaler and bson.ValueUnmarshaler
+// interfaces of the v2 driver itself, which don't depend on the driver types.
+// The v1 interfaces do, so with the v1 driver either register the codec of
+// this package:
+//
+//	reg := bson.NewRegistry()
+//	bsoncompat.Register(reg)
+//	client, err := mongo.Connect(ctx, options.Client().SetRegistry(reg))
+//
+// or use the Token type of this package for the fields.
+//
+// Tokens are stored as ObjectIDs. Both read ObjectIDs, 12 bytes binaries and
+// the string representations of tokens, and null as the nil token.
+package bsoncompat
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/zdz1715/xtoken"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+var tokenType = reflect.TypeOf(xtoken.Token{})
+
+// Register registers the Codec for xtoken.Token in r.
+func Register(r *bsoncodec.Registry) {
+	r.RegisterTypeEncoder(tokenType, Codec{})
+	r.RegisterTypeDecoder(tokenType, Codec{})
+}
+
+// Codec is a bsoncodec.ValueEncoder and bsoncodec.ValueDecoder for xtoken.Token.
+type Codec struct{}
+
+// EncodeValue implements bsoncodec.ValueEncoder.
+func (Codec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tokenType {
+		return bsoncodec.ValueEncoderError{Name: "xtoken.Codec", Types: []reflect.Type{tokenType}, Received: val}
+	}
+	typ, data, err := val.Interface().(xtoken.Token).MarshalBSONValue()
+	if err != nil {
+		return err
+	}
+	return bsonrw.Copier{}.CopyValueFromBytes(vw, bsontype.Type(typ), data)
+}
+
+// DecodeValue implements bsoncodec.ValueDecoder.
+func (Codec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tokenType {
+		return bsoncodec.ValueDecoderError{Name: "xtoken.Codec", Types: []reflect.Type{tokenType}, Received: val}
+	}
+	typ, data, err := bsonrw.Copier{}.CopyValueToBytes(vr)
+	if err != nil {
+		return err
+	}
+	var token xtoken.Token
+	if err := token.UnmarshalBSONValue(byte(typ), data); err != nil {
+		return fmt.Errorf("xtoken: decoding BSON %v: %w", typ, err)
+	}
+	val.Set(reflect.ValueOf(token))
+	return nil
+}
+
+// Token is an xtoken.Token implementing the bson.ValueMarshaler and
+// bson.ValueUnmarshaler interfaces of the v1 driver.
+type Token xtoken.Token
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (t Token) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	typ, data, err := xtoken.Token(t).MarshalBSONValue()
+	return bsontype.Type(typ), data, err
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler.
+func (t *Token) UnmarshalBSONValue(typ bsontype.Type, data []byte) error {
+	return (*xtoken.Token)(t).UnmarshalBSONValue(byte(typ), data)
+}
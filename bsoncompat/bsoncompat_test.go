@@ -0,0 +1,132 @@
+package bsoncompat
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	bsonv2 "go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type session struct {
+	ID   xtoken.Token `bson:"_id"`
+	Prev xtoken.Token `bson:"prev"`
+}
+
+type wrapped struct {
+	ID   Token `bson:"_id"`
+	Prev Token `bson:"prev"`
+}
+
+func marshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	reg := bson.NewRegistry()
+	Register(reg)
+	var buf bytes.Buffer
+	vw, err := bsonrw.NewBSONValueWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := bson.NewEncoder(vw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.SetRegistry(reg); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(v); err != nil {
+		t.Fatalf("Encode() err: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func unmarshal(t *testing.T, data []byte, v interface{}) {
+	t.Helper()
+	reg := bson.NewRegistry()
+	Register(reg)
+	dec, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.SetRegistry(reg); err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.Decode(v); err != nil {
+		t.Fatalf("Decode() err: %v", err)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	in := session{ID: xtoken.New()}
+	data := marshal(t, in)
+
+	// The token is a native ObjectID.
+	var raw bson.M
+	if err := bson.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("bson.Unmarshal() err: %v", err)
+	}
+	if oid, ok := raw["_id"].(primitive.ObjectID); !ok || oid != primitive.ObjectID(in.ID) {
+		t.Errorf("_id = %#v, want ObjectID %x", raw["_id"], in.ID[:])
+	}
+
+	var out session
+	unmarshal(t, data, &out)
+	if out != in {
+		t.Errorf("Decode() = %+v, want %+v", out, in)
+	}
+}
+
+func TestCodecString(t *testing.T) {
+	token := xtoken.New()
+	data, err := bson.Marshal(bson.M{"_id": token.String(), "prev": token.Hex()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out session
+	unmarshal(t, data, &out)
+	if out.ID != token || out.Prev != token {
+		t.Errorf("Decode() = %+v, want %v twice", out, token)
+	}
+}
+
+func TestToken(t *testing.T) {
+	in := wrapped{ID: Token(xtoken.New())}
+	data, err := bson.Marshal(in)
+	if err != nil {
+		t.Fatalf("bson.Marshal() err: %v", err)
+	}
+	var out wrapped
+	if err := bson.Unmarshal(data, &out); err != nil {
+		t.Fatalf("bson.Unmarshal() err: %v", err)
+	}
+	if out != in {
+		t.Errorf("bson.Unmarshal() = %+v, want %+v", out, in)
+	}
+}
+
+// TestDriverV2 checks that xtoken.Token implements the interfaces of the v2
+// driver without any registration.
+func TestDriverV2(t *testing.T) {
+	in := session{ID: xtoken.New()}
+	data, err := bsonv2.Marshal(in)
+	if err != nil {
+		t.Fatalf("bson.Marshal() err: %v", err)
+	}
+	var raw bsonv2.M
+	if err := bsonv2.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("bson.Unmarshal() err: %v", err)
+	}
+	if oid, ok := raw["_id"].(bsonv2.ObjectID); !ok || oid != bsonv2.ObjectID(in.ID) {
+		t.Errorf("_id = %#v, want ObjectID %x", raw["_id"], in.ID[:])
+	}
+	var out session
+	if err := bsonv2.Unmarshal(data, &out); err != nil {
+		t.Fatalf("bson.Unmarshal() err: %v", err)
+	}
+	if out != in {
+		t.Errorf("bson.Unmarshal() = %+v, want %+v", out, in)
+	}
+}
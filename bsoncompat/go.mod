@@ -0,0 +1,11 @@
+module github.com/zdz1715/xtoken/bsoncompat
+
+go 1.22
+
+require (
+	github.com/zdz1715/xtoken v0.0.0
+	go.mongodb.org/mongo-driver v1.17.10
+	go.mongodb.org/mongo-driver/v2 v2.2.0
+)
+
+replace github.com/zdz1715/xtoken => ../
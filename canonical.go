@@ -0,0 +1,28 @@
+package xtoken
+
+// EncodeToStringCanonical returns the canonical string representation of token
+// with the alphabet of e, see Token.CanonicalString.
+func (e *Encoding) EncodeToStringCanonical(token Token) string {
+	text := make([]byte, encodedLen)
+	e.encodeOrder(text, token[:], valuePositions[:])
+	return string(text)
+}
+
+// CanonicalString returns a string representation of the token which, unlike
+// String, doesn't shuffle the value chars: the same token always gives the same
+// 32 chars, so they can be used as map keys or in unique indexes.
+// FromString reads it like any other representation, but it no longer hides
+// the order of the timestamp and counter chars.
+func (token Token) CanonicalString() string {
+	return StdEncoding.EncodeToStringCanonical(token)
+}
+
+// Canonicalize returns the canonical form of the string representation s,
+// e.g. to deduplicate tokens received as strings, see Token.CanonicalString.
+func Canonicalize(s string) (string, error) {
+	token, err := FromString(s)
+	if err != nil {
+		return "", err
+	}
+	return token.CanonicalString(), nil
+}
@@ -0,0 +1,41 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalString(t *testing.T) {
+	for _, v := range IDs {
+		s := v.token.CanonicalString()
+		if got := v.token.CanonicalString(); got != s {
+			t.Errorf("CanonicalString() = %q then %q, want a stable string", s, got)
+		}
+		got, err := FromString(s)
+		if err != nil || got != v.token {
+			t.Errorf("FromString(%q) = %v, %v, want %v", s, got, err, v.token)
+		}
+		c, err := Canonicalize(v.token.String())
+		if err != nil || c != s {
+			t.Errorf("Canonicalize() = %q, %v, want %q", c, err, s)
+		}
+	}
+}
+
+func TestCanonicalizeInvalid(t *testing.T) {
+	if _, err := Canonicalize("invalid"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Canonicalize() err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestEncodeToStringCanonical(t *testing.T) {
+	enc, err := NewEncoding(noLookAlikes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := New()
+	s := enc.EncodeToStringCanonical(token)
+	if got, err := enc.DecodeString(s); err != nil || got != token {
+		t.Errorf("DecodeString(%q) = %v, %v, want %v", s, got, err, token)
+	}
+}
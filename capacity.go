@@ -0,0 +1,71 @@
+package xtoken
+
+import (
+	"math"
+	"time"
+)
+
+// Sizes of the components of a Token, in bits.
+const (
+	TimestampBits = 32 // seconds since the epoch of the Generator
+	MachineIDBits = 16 // the third byte of the machine id is the tag
+	PidBits       = 16
+	CounterBits   = 24
+)
+
+// CapacityInfo describes the limits of the tokens of a Generator, see
+// Generator.Capacity.
+type CapacityInfo struct {
+	// TokensPerSecond is how many tokens a process generates per second before
+	// New waits for the next one, on average with WithRandomCounterStep.
+	TokensPerSecond uint64
+	// NodeBits is the number of bits telling processes apart, the machine id
+	// and the pid. Processes sharing them rely on their random counter start
+	// to generate distinct tokens, see EstimateCollisionProbability.
+	NodeBits int
+	// First and Last are the first and last seconds a token can hold.
+	First, Last time.Time
+}
+
+// Capacity returns the limits of the tokens of New, see Generator.Capacity.
+func Capacity() CapacityInfo {
+	return defaultGenerator.Capacity()
+}
+
+// Capacity returns the limits of the tokens of g, which depend on its epoch
+// and counter step.
+func (g *Generator) Capacity() CapacityInfo {
+	perSecond := uint64(1) << CounterBits
+	if g.maxStep > 1 {
+		// The mean step is (maxStep+1)/2.
+		perSecond = perSecond * 2 / (uint64(g.maxStep) + 1)
+	}
+	return CapacityInfo{
+		TokensPerSecond: perSecond,
+		NodeBits:        MachineIDBits + PidBits,
+		First:           time.Unix(g.epoch, 0),
+		Last:            time.Unix(g.epoch+1<<TimestampBits-1, 0),
+	}
+}
+
+// EstimateCollisionProbability estimates the probability that processes
+// generating rate tokens per second each ever generate the same token, e.g.
+// to check whether a fleet stays under an acceptable risk.
+//
+// Tokens of a process never collide, nor do the tokens of processes with a
+// distinct machine id and pid. Processes sharing them, which the birthday
+// bound gives for 32 bits of uniform ids, collide when the counter ranges they
+// use within a second overlap: they advance at the same rate from a random
+// start, so it's about 2*rate/(1<<24) for the lifetime of the processes. It
+// assumes ids spread uniformly, it's a lower bound when the machine ids of the
+// hosts are correlated, e.g. with SetMachineID.
+func EstimateCollisionProbability(rate float64, processes int) float64 {
+	if processes < 2 || rate <= 0 {
+		return 0
+	}
+	pairs := float64(processes) * float64(processes-1) / 2
+	shareNode := 1 / float64(uint64(1)<<(MachineIDBits+PidBits))
+	overlap := math.Min(1, 2*rate/float64(uint64(1)<<CounterBits))
+	// 1-exp(-x) without losing precision for the tiny x of common fleets.
+	return -math.Expm1(-pairs * shareNode * overlap)
+}
@@ -0,0 +1,65 @@
+package xtoken
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCapacity(t *testing.T) {
+	c := Capacity()
+	if c.TokensPerSecond != 1<<24 || c.NodeBits != 32 {
+		t.Errorf("Capacity() = %+v, want 1<<24 tokens per second and 32 node bits", c)
+	}
+	if !c.First.Equal(time.Unix(0, 0)) || c.Last.Unix() != math.MaxUint32 {
+		t.Errorf("Capacity() range = [%v, %v], want the uint32 range of seconds", c.First, c.Last)
+	}
+	// The bit sizes add up to the token.
+	if got := TimestampBits + MachineIDBits + 8 + PidBits + CounterBits; got != rawLen*8 {
+		t.Errorf("bit sizes add up to %d, want %d", got, rawLen*8)
+	}
+
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c = NewGenerator(WithEpoch(epoch), WithRandomCounterStep(3)).Capacity()
+	if c.TokensPerSecond != 1<<23 {
+		t.Errorf("TokensPerSecond = %d with a mean step of 2, want %d", c.TokensPerSecond, 1<<23)
+	}
+	if !c.First.Equal(epoch) || !c.Last.Equal(epoch.Add(math.MaxUint32*time.Second)) {
+		t.Errorf("Capacity() range = [%v, %v], want from %v", c.First, c.Last, epoch)
+	}
+}
+
+func TestEstimateCollisionProbability(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      float64
+		processes int
+		want      float64
+	}{
+		{"single process", 1 << 24, 1, 0},
+		{"no tokens", 0, 1000, 0},
+		// One pair sharing its node id out of 1<<32, overlapping at full rate.
+		{"pair at full rate", 1 << 24, 2, 1.0 / (1 << 32)},
+		{"pair at low rate", 1 << 10, 2, 1.0 / (1 << 32) * (1.0 / (1 << 13))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateCollisionProbability(tt.rate, tt.processes)
+			if math.Abs(got-tt.want) > tt.want*1e-6 {
+				t.Errorf("EstimateCollisionProbability(%v, %d) = %g, want %g", tt.rate, tt.processes, got, tt.want)
+			}
+		})
+	}
+	// More processes or a higher rate never lowers the estimate, and it stays a probability.
+	prev := 0.0
+	for n := 2; n <= 1<<20; n *= 2 {
+		p := EstimateCollisionProbability(1e6, n)
+		if p < prev || p > 1 {
+			t.Fatalf("EstimateCollisionProbability(1e6, %d) = %g after %g", n, p, prev)
+		}
+		prev = p
+	}
+	if p := EstimateCollisionProbability(1<<24, 1<<20); p < 0.999 {
+		t.Errorf("EstimateCollisionProbability() of 1<<20 processes at full rate = %g, want about 1", p)
+	}
+}
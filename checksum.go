@@ -0,0 +1,113 @@
+package xtoken
+
+const (
+	// ErrChecksum is returned by ParseChecked and DecodeCursor when the
+	// checksum chars don't match the token, which usually means it was
+	// mistyped or altered. It satisfies errors.Is(err, ErrInvalidToken) and
+	// errors.Is(err, ErrBadChecksum).
+	ErrChecksum invalidErr = "invalid Token: checksum mismatch"
+)
+
+const (
+	checkedEncodedLen = encodedLen + 2 // checked string encoded len
+
+	// crc12Poly is the CRC-12 polynomial x^12+x^11+x^3+x^2+x+1 without its x^12 term.
+	crc12Poly = 0x80F
+)
+
+// crc12 returns the CRC-12 of b, it detects all the error bursts of up to 12 bits.
+func crc12(b []byte) uint16 {
+	var crc uint16
+	for _, c := range b {
+		crc ^= uint16(c) << 4
+		for i := 0; i < 8; i++ {
+			if crc&0x800 != 0 {
+				crc = crc<<1 ^ crc12Poly
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc & 0xFFF
+}
+
+// StringChecked returns the string representation of the token followed by 2
+// chars of the same alphabet holding a CRC-12 of the 12 raw bytes: 34 chars in
+// total, which FromString rejects. ParseChecked detects any single mistyped
+// char.
+func (token Token) StringChecked() string {
+	text := make([]byte, checkedEncodedLen)
+	StdEncoding.encode(text, token[:])
+	crc := crc12(token[:])
+	text[encodedLen] = encoding[crc>>6]
+	text[encodedLen+1] = encoding[crc&encodingIdxMax]
+	return string(text)
+}
+
+// ParseChecked reads a Token from its StringChecked representation. It returns
+// an error wrapping ErrInvalidToken for malformed input and ErrChecksum when
+// the chars are well-formed but don't match the checksum.
+//
+// On top of the checksum, the order chars must be a permutation of the value
+// positions and the other chars must be the canonical encoding of the token,
+// since some chars can be changed without changing the decoded token.
+func ParseChecked(s string) (Token, error) {
+	if err := checkText(s, checkedEncodedLen, &StdEncoding.dec); err != nil {
+		return nilToken, err
+	}
+	token, err := FromString(s[:encodedLen])
+	if err != nil {
+		return nilToken, err
+	}
+	pos, _ := positions(StdEncoding, s)
+	var orderIdxs [len(pos)]int
+	var seen [encodedLen]bool
+	for k, p := range pos {
+		if seen[p] {
+			return nilToken, ErrChecksum
+		}
+		seen[p] = true
+		orderIdxs[k] = int(p)
+	}
+	var text [encodedLen]byte
+	StdEncoding.encodeOrder(text[:], token[:], orderIdxs[:])
+	if string(text[:]) != s[:encodedLen] {
+		return nilToken, ErrChecksum
+	}
+	crc := uint16(StdEncoding.dec[s[encodedLen]])<<6 | uint16(StdEncoding.dec[s[encodedLen+1]])
+	if crc != crc12(token[:]) {
+		return nilToken, ErrChecksum
+	}
+	return token, nil
+}
+
+// Checked is a Token serialized as its StringChecked representation, so that
+// a mistyped token is rejected when it's read back instead of decoding into
+// another valid token. Use it for fields typed by humans, such as support
+// ticket references or license keys:
+//
+//	type License struct {
+//		Key xtoken.Checked `json:"key"`
+//	}
+type Checked Token
+
+// String returns the StringChecked representation of the token.
+func (c Checked) String() string {
+	return Token(c).StringChecked()
+}
+
+// MarshalText implements encoding.TextMarshaler with StringChecked.
+func (c Checked) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseChecked, a
+// checksum mismatch satisfies errors.Is(err, ErrInvalidToken).
+func (c *Checked) UnmarshalText(text []byte) error {
+	token, err := ParseChecked(string(text))
+	if err != nil {
+		return err
+	}
+	*c = Checked(token)
+	return nil
+}
@@ -0,0 +1,93 @@
+package xtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCRC12(t *testing.T) {
+	// Check value of CRC-12/DECT, which has no reflection, init nor xorout.
+	if got := crc12([]byte("123456789")); got != 0xF5B {
+		t.Errorf("crc12() = %#x, want %#x", got, 0xF5B)
+	}
+}
+
+func TestStringChecked(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		token := New()
+		s := token.StringChecked()
+		if len(s) != checkedEncodedLen {
+			t.Fatalf("len(StringChecked()) = %d, want %d", len(s), checkedEncodedLen)
+		}
+		got, err := ParseChecked(s)
+		if err != nil || got != token {
+			t.Fatalf("ParseChecked(%q) = %v, %v, want %v", s, got, err, token)
+		}
+		if _, err := FromString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("FromString(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+		if _, err := ParseChecked(token.String()); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("ParseChecked(%q) err = %v, want %v", token.String(), err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestParseCheckedDetectsTypos(t *testing.T) {
+	n := 200
+	if testing.Short() {
+		n = 20
+	}
+	for i := 0; i < n; i++ {
+		s := New().StringChecked()
+		for pos := 0; pos < len(s); pos++ {
+			// Every other char of the alphabet, and a char out of it.
+			for _, c := range []byte(encoding + "!") {
+				if c == s[pos] {
+					continue
+				}
+				typo := s[:pos] + string(c) + s[pos+1:]
+				if got, err := ParseChecked(typo); err == nil {
+					t.Fatalf("ParseChecked(%q) = %v, typo of %q at %d not detected", typo, got, s, pos)
+				}
+			}
+		}
+	}
+}
+
+func TestParseCheckedChecksumError(t *testing.T) {
+	s := New().StringChecked()
+	c := encoding[(StdEncoding.dec[s[encodedLen+1]]+1)&encodingIdxMax]
+	if _, err := ParseChecked(s[:encodedLen+1] + string(c)); err != ErrChecksum {
+		t.Errorf("ParseChecked() err = %v, want %v", err, ErrChecksum)
+	}
+}
+
+func TestChecked(t *testing.T) {
+	type license struct {
+		Key Checked `json:"key"`
+	}
+	want := license{Key: Checked(New())}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() err: %v", err)
+	}
+	var got license
+	if err := json.Unmarshal(b, &got); err != nil || got != want {
+		t.Fatalf("json.Unmarshal(%s) = %v, %v, want %v", b, got, err, want)
+	}
+	if token, err := ParseChecked(got.Key.String()); err != nil || Checked(token) != got.Key {
+		t.Errorf("ParseChecked(String()) = %v, %v, want %v", token, err, got.Key)
+	}
+
+	s := want.Key.String()
+	c := encoding[(StdEncoding.dec[s[encodedLen+1]]+1)&encodingIdxMax]
+	var typo Checked
+	err = typo.UnmarshalText([]byte(s[:encodedLen+1] + string(c)))
+	if !errors.Is(err, ErrInvalidToken) || !errors.Is(err, ErrBadChecksum) {
+		t.Errorf("UnmarshalText() err = %v, want %v", err, ErrChecksum)
+	}
+	if err := typo.UnmarshalText([]byte(Token(want.Key).String())); !errors.Is(err, ErrBadLength) {
+		t.Errorf("UnmarshalText() of an unchecked string err = %v, want %v", err, ErrBadLength)
+	}
+}
@@ -0,0 +1,226 @@
+// Command xtoken generates, inspects and converts tokens.
+//
+// Usage:
+//
+//	xtoken generate [-n count] [-t RFC3339 time] [-format format]
+//	xtoken inspect [-text] [token ...]
+//	xtoken convert [-to format] [value ...]
+//
+// The formats are string, canonical, hex, base32, base58, base64 and uuid, see
+// xtoken.Style and Token.UUIDString.
+//
+// inspect and convert read whitespace separated values from stdin when no
+// argument is given, in any format. They detect it by its length: 32 chars for
+// the string encoding, 36 for uuid, 24 for hex, 20 for base32, 17 for base58
+// and 16 for base64.
+//
+// inspect prints one JSON object per token, its xtoken.Decomposed components
+// along with the token as given, or a line of text with -text.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zdz1715/xtoken"
+)
+
+const usage = `usage:
+  xtoken generate [-n count] [-t RFC3339 time] [-format format]
+  xtoken inspect [-text] [token ...]
+  xtoken convert [-to format] [value ...]
+
+formats: string, canonical, hex, base32, base58, base64, uuid
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "generate", "gen":
+		err = generate(args[1:], stdout, stderr)
+	case "inspect":
+		err = inspect(args[1:], stdin, stdout, stderr)
+	case "convert":
+		err = convert(args[1:], stdin, stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		err = fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "xtoken: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func generate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("generate", stderr)
+	n := fs.Int("n", 1, "number of tokens to generate")
+	at := fs.String("t", "", "time of the tokens, RFC3339 (default now)")
+	name := fs.String("format", "string", "output format")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *n < 0 {
+		return fmt.Errorf("invalid count %d", *n)
+	}
+	format, err := formatter(*name)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	if *at == "" {
+		for _, token := range xtoken.NewBatch(*n) {
+			fmt.Fprintln(w, format(token))
+		}
+		return w.Flush()
+	}
+	t, err := time.Parse(time.RFC3339, *at)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < *n; i++ {
+		fmt.Fprintln(w, format(xtoken.NewWithTime(t)))
+	}
+	return w.Flush()
+}
+
+// inspection is the JSON object printed by inspect for each token.
+type inspection struct {
+	Token string `json:"token"`
+	xtoken.Decomposed
+}
+
+func inspect(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("inspect", stderr)
+	asText := fs.Bool("text", false, "print a line of text instead of JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	enc := json.NewEncoder(w)
+	return each(fs.Args(), stdin, func(s string) error {
+		token, err := parse(s)
+		if err != nil {
+			return fmt.Errorf("%q: %w", s, err)
+		}
+		d := token.Decompose()
+		if *asText {
+			_, err = fmt.Fprintf(w, "token=%s %s\n", s, d)
+			return err
+		}
+		return enc.Encode(inspection{Token: s, Decomposed: d})
+	})
+}
+
+func convert(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	to := fs.String("to", "hex", "output format")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := formatter(*to)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	return each(fs.Args(), stdin, func(s string) error {
+		token, err := parse(s)
+		if err != nil {
+			return fmt.Errorf("%q: %w", s, err)
+		}
+		_, err = fmt.Fprintln(w, format(token))
+		return err
+	})
+}
+
+// styles are the formats of formatter besides uuid.
+var styles = []xtoken.Style{
+	xtoken.StyleString,
+	xtoken.StyleCanonical,
+	xtoken.StyleHex,
+	xtoken.StyleBase32,
+	xtoken.StyleBase58,
+	xtoken.StyleBase64URL,
+}
+
+// formatter returns the function printing tokens in the format name.
+func formatter(name string) (func(xtoken.Token) string, error) {
+	switch name {
+	case "uuid":
+		return xtoken.Token.UUIDString, nil
+	case "base64":
+		name = xtoken.StyleBase64URL.String()
+	}
+	for _, style := range styles {
+		if style.String() == name {
+			return func(token xtoken.Token) string { return token.EncodeTo(style) }, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown format %q", name)
+}
+
+// parse reads a token in any format, detected by its length.
+func parse(s string) (xtoken.Token, error) {
+	switch len(s) {
+	case 36:
+		return xtoken.FromUUIDString(s)
+	case 24:
+		return xtoken.FromHex(s)
+	case 20:
+		return xtoken.DecodeFrom(s, xtoken.StyleBase32)
+	case 17:
+		return xtoken.DecodeFrom(s, xtoken.StyleBase58)
+	case 16:
+		return xtoken.FromBase64(s)
+	}
+	return xtoken.FromString(s)
+}
+
+// each calls fn for every arg, or for every word of stdin if there is no arg.
+func each(args []string, stdin io.Reader, fn func(string) error) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(stdin)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
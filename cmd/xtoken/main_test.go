@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zdz1715/xtoken"
+)
+
+func runCmd(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestGenerate(t *testing.T) {
+	code, out, errOut := runCmd(t, "", "generate", "-n", "5", "-t", "2011-03-22T17:50:19Z")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, errOut)
+	}
+	lines := strings.Fields(out)
+	if len(lines) != 5 {
+		t.Fatalf("generate printed %d tokens, want 5", len(lines))
+	}
+	for _, line := range lines {
+		token, err := xtoken.FromString(line)
+		if err != nil {
+			t.Fatalf("FromString(%q) err: %v", line, err)
+		}
+		if got, want := token.Time(), time.Date(2011, 3, 22, 17, 50, 19, 0, time.UTC); !got.Equal(want) {
+			t.Errorf("Time() = %v, want %v", got, want)
+		}
+	}
+
+	if code, out, _ := runCmd(t, "", "generate"); code != 0 || len(strings.Fields(out)) != 1 {
+		t.Errorf("generate = %d, %q, want one token", code, out)
+	}
+	if code, _, _ := runCmd(t, "", "generate", "-t", "yesterday"); code != 1 {
+		t.Errorf("generate with an invalid time exit code = %d, want 1", code)
+	}
+	if code, _, _ := runCmd(t, "", "generate", "-n", "-1"); code != 1 {
+		t.Errorf("generate with an invalid count exit code = %d, want 1", code)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	token := xtoken.Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	s := token.String()
+	code, out, errOut := runCmd(t, s+"\n"+s+"\n", "inspect")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, errOut)
+	}
+	want := `{"token":"` + s + `","time":"2011-03-22T17:50:19Z","machine":"60f486","pid":58408,"counter":4271561}` + "\n"
+	if out != want+want {
+		t.Errorf("inspect = %q, want %q twice", out, want)
+	}
+
+	code, out, errOut = runCmd(t, "", "inspect", "-text", s)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, errOut)
+	}
+	want = "token=" + s + " time=2011-03-22T17:50:19Z machine=60f486 pid=58408 counter=4271561\n"
+	if out != want {
+		t.Errorf("inspect -text = %q, want %q", out, want)
+	}
+}
+
+func TestInspectInvalid(t *testing.T) {
+	code, _, errOut := runCmd(t, "", "inspect", "not-a-token")
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(errOut, "length 11, want 32") {
+		t.Errorf("stderr = %q, want the parse error", errOut)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	token := xtoken.Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	const hex, b64 = "4d88e15b60f486e428412dc9", "TYjhW2D0huQoQS3J"
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"convert", token.String()}, hex + "\n"},
+		{[]string{"convert", "-to", "base64", hex}, b64 + "\n"},
+		{[]string{"convert", "-to", "hex", b64}, hex + "\n"},
+	}
+	for _, tt := range tests {
+		code, out, errOut := runCmd(t, "", tt.args...)
+		if code != 0 {
+			t.Fatalf("%v exit code = %d, stderr: %s", tt.args, code, errOut)
+		}
+		if out != tt.want {
+			t.Errorf("%v = %q, want %q", tt.args, out, tt.want)
+		}
+	}
+
+	code, out, _ := runCmd(t, hex+" "+b64, "convert", "-to", "string")
+	if code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	for _, line := range strings.Fields(out) {
+		if got, err := xtoken.FromString(line); err != nil || got != token {
+			t.Errorf("convert -to string = %q, want an encoding of %v", line, token)
+		}
+	}
+
+	if code, _, _ := runCmd(t, "", "convert", "-to", "base36", hex); code != 1 {
+		t.Errorf("unknown format exit code = %d, want 1", code)
+	}
+	if code, _, _ := runCmd(t, "", "convert", "zz88e15b60f486e428412dc9"); code != 1 {
+		t.Errorf("invalid hex exit code = %d, want 1", code)
+	}
+}
+
+func TestConvertFormats(t *testing.T) {
+	token := xtoken.Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	const hex = "4d88e15b60f486e428412dc9"
+	for _, format := range []string{"string", "canonical", "hex", "base32", "base58", "base64", "base64url", "uuid"} {
+		code, out, errOut := runCmd(t, "", "convert", "-to", format, hex)
+		if code != 0 {
+			t.Fatalf("convert -to %s exit code = %d, stderr: %s", format, code, errOut)
+		}
+		// Every format converts back.
+		code, back, errOut := runCmd(t, out, "convert")
+		if code != 0 || back != hex+"\n" {
+			t.Errorf("convert %q = %d, %q, %s, want %q", out, code, back, errOut, hex)
+		}
+	}
+	if code, out, _ := runCmd(t, "", "convert", "-to", "uuid", hex); out != token.UUIDString()+"\n" {
+		t.Errorf("convert -to uuid = %d, %q, want %q", code, out, token.UUIDString())
+	}
+	if code, out, _ := runCmd(t, "", "inspect", token.UUIDString()); code != 0 || !strings.Contains(out, `"counter":4271561`) {
+		t.Errorf("inspect of a uuid = %d, %q", code, out)
+	}
+}
+
+func TestGenerateFormat(t *testing.T) {
+	code, out, errOut := runCmd(t, "", "generate", "-n", "3", "-format", "uuid")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, errOut)
+	}
+	lines := strings.Fields(out)
+	if len(lines) != 3 {
+		t.Fatalf("generate printed %d tokens, want 3", len(lines))
+	}
+	for _, line := range lines {
+		if _, err := xtoken.FromUUIDString(line); err != nil {
+			t.Errorf("FromUUIDString(%q) err: %v", line, err)
+		}
+	}
+	if code, _, _ := runCmd(t, "", "generate", "-format", "base36"); code != 1 {
+		t.Errorf("generate with an unknown format exit code = %d, want 1", code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if code, _, errOut := runCmd(t, ""); code != 2 || !strings.Contains(errOut, "usage") {
+		t.Errorf("no command = %d, %q, want usage", code, errOut)
+	}
+	if code, _, _ := runCmd(t, "", "frobnicate"); code != 1 {
+		t.Errorf("unknown command exit code = %d, want 1", code)
+	}
+	if code, _, _ := runCmd(t, "", "generate", "-h"); code != 0 {
+		t.Errorf("generate -h exit code = %d, want 0", code)
+	}
+}
@@ -0,0 +1,43 @@
+package xtoken
+
+const (
+	compactEncodedLen = 20 // compact string encoded len
+
+	// compactEncoding is the first half of the alphabet of String, as a 5-bit
+	// symbol only needs 32 of them.
+	compactEncoding = "aAbBcCdDeEfFgGhHiIjJkKlLmMnNoOpP"
+)
+
+// compactDec is the decoding map for compactEncoding.
+var compactDec [256]byte
+
+func init() {
+	for i := 0; i < len(compactDec); i++ {
+		compactDec[i] = 0xFF
+	}
+	for i := 0; i < len(compactEncoding); i++ {
+		compactDec[compactEncoding[i]] = byte(i)
+	}
+}
+
+// CompactString returns a 20 chars representation of the token for
+// space-constrained places such as SMS links or QR codes (char set is a-p and
+// A-P). Unlike String, symbols are at fixed positions and there are no order
+// chars, so a token has a single CompactString.
+func (token Token) CompactString() string {
+	text := make([]byte, compactEncodedLen)
+	encodeBase32(text, token[:], compactEncoding)
+	return string(text)
+}
+
+// FromCompactString reads a token from its CompactString representation.
+func FromCompactString(s string) (Token, error) {
+	var token Token
+	if err := checkText(s, compactEncodedLen, &compactDec); err != nil {
+		return token, err
+	}
+	if !decodeBase32(token[:], s, &compactDec) {
+		return nilToken, ErrInconsistentToken
+	}
+	return token, nil
+}
@@ -0,0 +1,72 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompactString(t *testing.T) {
+	tokens := []Token{New(), {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}
+	for _, v := range IDs {
+		tokens = append(tokens, v.token)
+	}
+	for _, token := range tokens {
+		s := token.CompactString()
+		if len(s) != compactEncodedLen {
+			t.Errorf("len(CompactString()) = %d, want %d", len(s), compactEncodedLen)
+		}
+		got, err := FromCompactString(s)
+		if err != nil {
+			t.Fatalf("FromCompactString(%q) err: %v", s, err)
+		}
+		if got != token {
+			t.Errorf("FromCompactString(%q) = %x, want %x", s, got[:], token[:])
+		}
+		if got, err := FromString(s); err != nil || got != token {
+			t.Errorf("FromString(%q) = %x, %v, want %x", s, got[:], err, token[:])
+		}
+	}
+	if got, want := nilToken.CompactString(), "aaaaaaaaaaaaaaaaaaaa"; got != want {
+		t.Errorf("CompactString() = %q, want %q", got, want)
+	}
+	if got, want := IDs[0].token.CompactString(), "ElchblNapjBhefbACLci"; got != want {
+		t.Errorf("CompactString() = %q, want %q", got, want)
+	}
+}
+
+func TestFromCompactStringInvalid(t *testing.T) {
+	s := IDs[0].token.CompactString()
+	for _, s := range []string{
+		"",
+		s[:19],
+		s + "a",
+		s[:19] + "A", // padding bits set
+		s[:19] + "q", // out of the compact alphabet
+		s[:19] + "0",
+		IDs[0].token.SortableString(),
+		IDs[0].token.String(),
+	} {
+		if _, err := FromCompactString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromCompactString(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+	if _, err := FromString(s[:19] + "q"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("FromString() err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	token := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = token.String()
+	}
+}
+
+func BenchmarkCompactString(b *testing.B) {
+	token := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = token.CompactString()
+	}
+}
@@ -0,0 +1,6 @@
+// Package compattest holds the tests round-tripping tokens through
+// third-party encoders whose interfaces xtoken.Token implements without
+// importing them, such as gopkg.in/yaml.v3 and
+// github.com/vmihailenco/msgpack/v5, so that the xtoken module keeps
+// no dependencies. It has no API.
+package compattest
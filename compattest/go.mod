@@ -0,0 +1,14 @@
+module github.com/zdz1715/xtoken/compattest
+
+go 1.22
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	github.com/zdz1715/xtoken v0.0.0
+	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+
+replace github.com/zdz1715/xtoken => ../
@@ -0,0 +1,75 @@
+package compattest
+
+import (
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/zdz1715/xtoken"
+)
+
+type record struct {
+	ID      xtoken.Token
+	Unset   xtoken.Token
+	Pointer *xtoken.Token
+	Tokens  []xtoken.Token
+	Names   map[xtoken.Token]string
+}
+
+func TestMsgpack(t *testing.T) {
+	a, b := xtoken.New(), xtoken.New()
+	want := record{
+		ID:      a,
+		Pointer: &b,
+		Tokens:  []xtoken.Token{a, b},
+		Names:   map[xtoken.Token]string{a: "a", b: "b"},
+	}
+	data, err := msgpack.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() err: %v", err)
+	}
+	// Tokens are 12 bytes binaries, not arrays of 12 numbers.
+	var raw map[string]interface{}
+	if err := msgpack.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal() err: %v", err)
+	}
+	if id, ok := raw["ID"].([]byte); !ok || string(id) != string(a.Bytes()) {
+		t.Errorf("Marshal() ID = %#v, want the raw bytes %x", raw["ID"], a.Bytes())
+	}
+
+	var got record
+	if err := msgpack.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() err: %v", err)
+	}
+	if got.ID != a || !got.Unset.IsZero() || got.Pointer == nil || *got.Pointer != b {
+		t.Errorf("fields = %v %v %v, want %v, the nil token, %v", got.ID, got.Unset, got.Pointer, a, b)
+	}
+	if len(got.Tokens) != 2 || got.Tokens[0] != a || got.Tokens[1] != b {
+		t.Errorf("Tokens = %v, want %v", got.Tokens, want.Tokens)
+	}
+	if len(got.Names) != 2 || got.Names[a] != "a" || got.Names[b] != "b" {
+		t.Errorf("Names = %v, want %v", got.Names, want.Names)
+	}
+}
+
+func TestMsgpackString(t *testing.T) {
+	// Tokens written by other encoders as strings are read back.
+	token := xtoken.New()
+	for _, s := range []string{token.String(), token.Hex()} {
+		data, err := msgpack.Marshal(map[string]string{"ID": s})
+		if err != nil {
+			t.Fatalf("Marshal() err: %v", err)
+		}
+		var got record
+		if err := msgpack.Unmarshal(data, &got); err != nil || got.ID != token {
+			t.Errorf("Unmarshal(%q) = %v, %v, want %v", s, got.ID, err, token)
+		}
+	}
+	data, err := msgpack.Marshal(map[string]interface{}{"Pointer": nil})
+	if err != nil {
+		t.Fatalf("Marshal() err: %v", err)
+	}
+	got := record{Pointer: &token}
+	if err := msgpack.Unmarshal(data, &got); err != nil || got.Pointer != nil {
+		t.Errorf("Unmarshal(nil) = %v, %v, want a nil pointer", got.Pointer, err)
+	}
+}
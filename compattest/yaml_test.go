@@ -0,0 +1,89 @@
+package compattest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+)
+
+type config struct {
+	Seed    xtoken.Token            `yaml:"seed"`
+	Unset   xtoken.Token            `yaml:"unset"`
+	Pointer *xtoken.Token           `yaml:"pointer"`
+	Tokens  []xtoken.Token          `yaml:"tokens"`
+	Names   map[xtoken.Token]string `yaml:"names"`
+}
+
+func newConfig() config {
+	a, b := xtoken.New(), xtoken.New()
+	return config{
+		Seed:    a,
+		Pointer: &b,
+		Tokens:  []xtoken.Token{a, b},
+		Names:   map[xtoken.Token]string{a: "a", b: "b"},
+	}
+}
+
+func checkConfig(t *testing.T, got, want config) {
+	t.Helper()
+	if got.Seed != want.Seed || !got.Unset.IsZero() || got.Pointer == nil || *got.Pointer != *want.Pointer {
+		t.Errorf("fields = %v %v %v, want %v %v %v", got.Seed, got.Unset, got.Pointer, want.Seed, want.Unset, want.Pointer)
+	}
+	if len(got.Tokens) != 2 || got.Tokens[0] != want.Tokens[0] || got.Tokens[1] != want.Tokens[1] {
+		t.Errorf("Tokens = %v, want %v", got.Tokens, want.Tokens)
+	}
+	if len(got.Names) != 2 || got.Names[want.Tokens[0]] != "a" || got.Names[want.Tokens[1]] != "b" {
+		t.Errorf("Names = %v, want %v", got.Names, want.Names)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	want := newConfig()
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() err: %v", err)
+	}
+	// Tokens are strings, not arrays of 12 numbers.
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("Unmarshal() err: %v", err)
+	}
+	if s, ok := raw["seed"].(string); !ok || xtoken.MustFromString(s) != want.Seed {
+		t.Errorf("Marshal() = %s, want the seed as a string", b)
+	}
+	var got config
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal() err: %v", err)
+	}
+	checkConfig(t, got, want)
+}
+
+func TestYAMLv2(t *testing.T) {
+	want := newConfig()
+	b, err := yamlv2.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() err: %v", err)
+	}
+	var got config
+	if err := yamlv2.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal() err: %v", err)
+	}
+	checkConfig(t, got, want)
+}
+
+func TestYAMLEmpty(t *testing.T) {
+	token := xtoken.New()
+	got := config{Seed: token, Pointer: &token}
+	if err := yaml.Unmarshal([]byte("seed: ''\npointer: null\n"), &got); err != nil {
+		t.Fatalf("Unmarshal() err: %v", err)
+	}
+	if !got.Seed.IsZero() || got.Pointer != nil {
+		t.Errorf("Unmarshal() = %v, %v, want the nil token and a nil pointer", got.Seed, got.Pointer)
+	}
+	if err := yaml.Unmarshal([]byte("seed: not a token\n"), &got); !errors.Is(err, xtoken.ErrInvalidToken) {
+		t.Errorf("Unmarshal() of an invalid token err = %v, want %v", err, xtoken.ErrInvalidToken)
+	}
+}
@@ -0,0 +1,20 @@
+package xtoken
+
+import (
+	"context"
+)
+
+// contextKey is the key of the token in a context.Context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying token, e.g. the request id of the
+// request ctx belongs to.
+func NewContext(ctx context.Context, token Token) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// FromContext returns the token carried by ctx, if any, see NewContext.
+func FromContext(ctx context.Context) (Token, bool) {
+	token, ok := ctx.Value(contextKey{}).(Token)
+	return token, ok
+}
@@ -0,0 +1,26 @@
+package xtoken
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Errorf("FromContext() of an empty context = true, want false")
+	}
+	want := New()
+	ctx := NewContext(context.Background(), want)
+	if got, ok := FromContext(ctx); !ok || got != want {
+		t.Errorf("FromContext() = %v, %v, want %v", got, ok, want)
+	}
+	// The nil token is carried as well.
+	if got, ok := FromContext(NewContext(ctx, nilToken)); !ok || !got.IsZero() {
+		t.Errorf("FromContext() = %v, %v, want the nil token", got, ok)
+	}
+	// Another key of the same underlying type doesn't collide.
+	type otherKey struct{}
+	if _, ok := FromContext(context.WithValue(context.Background(), otherKey{}, want)); ok {
+		t.Errorf("FromContext() = true for another key, want false")
+	}
+}
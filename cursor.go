@@ -0,0 +1,93 @@
+package xtoken
+
+import (
+	"encoding/base64"
+)
+
+const (
+	cursorEncodedLen = 1 + base64EncodedLen + 2 // direction, token and checksum chars
+
+	cursorAsc  = 'a' // direction char of ascending cursors
+	cursorDesc = 'd' // direction char of descending cursors
+)
+
+// Next returns the smallest token greater than token in byte order, it
+// reports false when token is the largest one, all 0xFF bytes.
+func (token Token) Next() (Token, bool) {
+	for i := rawLen - 1; i >= 0; i-- {
+		token[i]++
+		if token[i] != 0 {
+			return token, true
+		}
+	}
+	return nilToken, false
+}
+
+// Prev returns the largest token less than token in byte order, it reports
+// false when token is the nil token.
+func (token Token) Prev() (Token, bool) {
+	for i := rawLen - 1; i >= 0; i-- {
+		token[i]--
+		if token[i] != 0xFF {
+			return token, true
+		}
+	}
+	return nilToken, false
+}
+
+// EncodeCursor returns an opaque, URL-safe pagination cursor carrying t and
+// the direction desc: a direction char, the 16 chars base64.RawURLEncoding
+// representation of t and 2 checksum chars, 19 chars in total.
+func EncodeCursor(t Token, desc bool) string {
+	text := make([]byte, cursorEncodedLen)
+	text[0] = cursorAsc
+	if desc {
+		text[0] = cursorDesc
+	}
+	base64.RawURLEncoding.Encode(text[1:], t[:])
+	crc := cursorChecksum(text[0], t)
+	text[cursorEncodedLen-2] = base64Encoding[crc>>6]
+	text[cursorEncodedLen-1] = base64Encoding[crc&encodingIdxMax]
+	return string(text)
+}
+
+// DecodeCursor reads a cursor returned by EncodeCursor. It returns an error
+// wrapping ErrInvalidToken for malformed input and ErrChecksum when the
+// cursor was altered.
+func DecodeCursor(s string) (t Token, desc bool, err error) {
+	if len(s) != cursorEncodedLen {
+		return nilToken, false, &InvalidLengthError{Got: len(s), Want: cursorEncodedLen}
+	}
+	switch s[0] {
+	case cursorAsc:
+	case cursorDesc:
+		desc = true
+	default:
+		return nilToken, false, &InvalidCharacterError{Char: s[0], Pos: 0}
+	}
+	if err := checkText(s[cursorEncodedLen-2:], 2, &base64Dec); err != nil {
+		charErr := err.(*InvalidCharacterError)
+		charErr.Pos += cursorEncodedLen - 2
+		return nilToken, false, charErr
+	}
+	t, err = FromBase64(s[1 : 1+base64EncodedLen])
+	if err != nil {
+		if charErr, ok := err.(*InvalidCharacterError); ok {
+			charErr.Pos++
+		}
+		return nilToken, false, err
+	}
+	crc := uint16(base64Dec[s[cursorEncodedLen-2]])<<6 | uint16(base64Dec[s[cursorEncodedLen-1]])
+	if crc != cursorChecksum(s[0], t) {
+		return nilToken, false, ErrChecksum
+	}
+	return t, desc, nil
+}
+
+// cursorChecksum returns the CRC-12 of the direction char and the raw token.
+func cursorChecksum(dir byte, t Token) uint16 {
+	var b [1 + rawLen]byte
+	b[0] = dir
+	copy(b[1:], t[:])
+	return crc12(b[:])
+}
@@ -0,0 +1,87 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+var maxToken = Token{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+
+func TestNextPrev(t *testing.T) {
+	tests := []struct {
+		token, next Token
+	}{
+		{Token{}, Token{11: 1}},
+		{Token{11: 0xFE}, Token{11: 0xFF}},
+		{Token{11: 0xFF}, Token{10: 1}},
+		{Token{9: 0x12, 10: 0xFF, 11: 0xFF}, Token{9: 0x13}},
+		{Token{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE}, maxToken},
+		{Token{0, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, Token{1}},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.token.Next(); !ok || got != tt.next {
+			t.Errorf("%x.Next() = %x, %v, want %x", tt.token[:], got[:], ok, tt.next[:])
+		}
+		if got, ok := tt.next.Prev(); !ok || got != tt.token {
+			t.Errorf("%x.Prev() = %x, %v, want %x", tt.next[:], got[:], ok, tt.token[:])
+		}
+		if next, _ := tt.token.Next(); !tt.token.Less(next) {
+			t.Errorf("%x.Next() is not greater", tt.token[:])
+		}
+	}
+	if got, ok := maxToken.Next(); ok || !got.IsZero() {
+		t.Errorf("maxToken.Next() = %x, %v, want the nil token and false", got[:], ok)
+	}
+	if got, ok := nilToken.Prev(); ok || !got.IsZero() {
+		t.Errorf("nilToken.Prev() = %x, %v, want the nil token and false", got[:], ok)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	for _, token := range []Token{nilToken, maxToken, New()} {
+		for _, desc := range []bool{false, true} {
+			s := EncodeCursor(token, desc)
+			if len(s) != cursorEncodedLen {
+				t.Fatalf("len(EncodeCursor()) = %d, want %d", len(s), cursorEncodedLen)
+			}
+			got, gotDesc, err := DecodeCursor(s)
+			if err != nil || got != token || gotDesc != desc {
+				t.Errorf("DecodeCursor(%q) = %x, %v, %v, want %x, %v", s, got[:], gotDesc, err, token[:], desc)
+			}
+			if _, err := FromString(s); err == nil {
+				t.Errorf("FromString(%q) err = nil, want an error", s)
+			}
+		}
+	}
+}
+
+func TestDecodeCursorTampered(t *testing.T) {
+	s := EncodeCursor(New(), true)
+	for pos := 0; pos < len(s); pos++ {
+		for _, c := range []byte(base64Encoding + "!") {
+			if c == s[pos] {
+				continue
+			}
+			tampered := s[:pos] + string(c) + s[pos+1:]
+			if got, _, err := DecodeCursor(tampered); err == nil {
+				t.Fatalf("DecodeCursor(%q) = %v, tampering of %q at %d not detected", tampered, got, s, pos)
+			}
+		}
+	}
+	for _, in := range []string{"", s[1:], s + "a", "x" + s[1:]} {
+		if _, _, err := DecodeCursor(in); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("DecodeCursor(%q) err = %v, want %v", in, err, ErrInvalidToken)
+		}
+	}
+	var charErr *InvalidCharacterError
+	if _, _, err := DecodeCursor(s[:5] + "!" + s[6:]); !errors.As(err, &charErr) || charErr.Pos != 5 {
+		t.Errorf("DecodeCursor() err = %v, want an *InvalidCharacterError at 5", err)
+	}
+	if _, _, err := DecodeCursor(s[:17] + "!" + s[18:]); !errors.As(err, &charErr) || charErr.Pos != 17 {
+		t.Errorf("DecodeCursor() err = %v, want an *InvalidCharacterError at 17", err)
+	}
+	flipped := "a" + s[1:]
+	if _, _, err := DecodeCursor(flipped); err != ErrChecksum {
+		t.Errorf("DecodeCursor(%q) err = %v, want %v", flipped, err, ErrChecksum)
+	}
+}
@@ -0,0 +1,230 @@
+package xtoken
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+)
+
+// Encoding is a 64 symbols alphabet for the string representation of tokens,
+// the symbols are shuffled with the same self-describing layout as String.
+// StdEncoding is the default one.
+type Encoding struct {
+	alphabet string
+	dec      [256]byte
+}
+
+// StdEncoding is the default Encoding, used by String and FromString.
+var StdEncoding = mustNewEncoding(encoding)
+
+// NewEncoding returns an Encoding for alphabet, which must be made of 64
+// distinct bytes.
+func NewEncoding(alphabet string) (*Encoding, error) {
+	if len(alphabet) != len(encoding) {
+		return nil, fmt.Errorf("xtoken: alphabet must be %d bytes long, got %d", len(encoding), len(alphabet))
+	}
+	e := &Encoding{alphabet: alphabet}
+	for i := 0; i < len(e.dec); i++ {
+		e.dec[i] = 0xFF
+	}
+	for i := 0; i < len(alphabet); i++ {
+		if e.dec[alphabet[i]] != 0xFF {
+			return nil, fmt.Errorf("xtoken: duplicate symbol %q in alphabet", alphabet[i])
+		}
+		e.dec[alphabet[i]] = byte(i)
+	}
+	return e, nil
+}
+
+func mustNewEncoding(alphabet string) *Encoding {
+	e, err := NewEncoding(alphabet)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// EncodeToString returns the string representation of token with the alphabet of e.
+func (e *Encoding) EncodeToString(token Token) string {
+	text := make([]byte, encodedLen)
+	e.encode(text, token[:])
+	return string(text)
+}
+
+// appendEncode appends the string representation of token with the alphabet
+// of e to dst.
+func (e *Encoding) appendEncode(dst []byte, token Token) []byte {
+	dst = slices.Grow(dst, encodedLen)
+	n := len(dst)
+	dst = dst[:n+encodedLen]
+	e.encode(dst[n:], token[:])
+	return dst
+}
+
+// DecodeString reads a token from its string representation with the alphabet of e.
+func (e *Encoding) DecodeString(s string) (Token, error) {
+	var token Token
+	err := e.decodeText(&token, []byte(s))
+	return token, err
+}
+
+// decodeText validates and decodes text into token.
+func (e *Encoding) decodeText(token *Token, text []byte) error {
+	if err := checkText(text, encodedLen, &e.dec); err != nil {
+		return err
+	}
+	if !e.decode(token, text) {
+		*token = nilToken
+		return ErrInconsistentToken
+	}
+	return nil
+}
+
+// encode by unrolling the stdlib base32 algorithm + removing all safe checks
+// value: 0,3,5,7,9,11,17,19,21,23,27,31
+// padding: 4,8,12,16,20,24,28,29
+// objectIDCounter order: 1,14,25
+// time order: 2,13,22,30
+// machine id order: 6,15,26
+// pid order: 10,18
+func (e *Encoding) encode(dst, token []byte) {
+	_ = dst[encodedLen-1]
+	_ = token[rawLen-1]
+	orderIdxs := valuePositions
+	// Fisher-Yates shuffle with math/rand/v2, its ChaCha8 source is per thread
+	// and seeded by the runtime, so it doesn't lock nor is predictable.
+	// A single 64-bit draw is enough for the 12! ≈ 2^29 layouts: each index is
+	// the high word of x*(i+1) and the low word carries the remaining entropy,
+	// the bias is below 12!/2^64.
+	x := rand.Uint64()
+	for i := len(orderIdxs) - 1; i > 0; i-- {
+		hi, lo := bits.Mul64(x, uint64(i+1))
+		j := int(hi)
+		x = lo
+		orderIdxs[i], orderIdxs[j] = orderIdxs[j], orderIdxs[i]
+	}
+	e.encodeOrder(dst, token, orderIdxs[:])
+}
+
+// encodeOrder encodes token with the value positions of orderIdxs, which must
+// be a permutation of valuePositions.
+func (e *Encoding) encodeOrder(dst, token []byte, orderIdxs []int) {
+	_ = dst[encodedLen-1]
+	_ = token[rawLen-1]
+	_ = orderIdxs[11]
+
+	// order: 12 bytes
+	// time order: 2, 13 ,22 ,30
+	dst[2] = e.alphabet[orderIdxs[0]]
+	dst[13] = e.alphabet[orderIdxs[1]]
+	dst[22] = e.alphabet[orderIdxs[2]]
+	dst[30] = e.alphabet[orderIdxs[3]]
+	// machine id order
+	dst[6] = e.alphabet[orderIdxs[4]]
+	dst[15] = e.alphabet[orderIdxs[5]]
+	dst[26] = e.alphabet[orderIdxs[6]]
+	// pid order
+	dst[10] = e.alphabet[orderIdxs[7]]
+	dst[18] = e.alphabet[orderIdxs[8]]
+	// objectIDCounter order: 1, 14 ,25
+	dst[1] = e.alphabet[orderIdxs[9]]
+	dst[14] = e.alphabet[orderIdxs[10]]
+	dst[25] = e.alphabet[orderIdxs[11]]
+
+	// set value and padding
+	dst[orderIdxs[0]] = e.alphabet[(token[0]>>3)&encodingIdxMax]
+	dst[orderIdxs[1]] = e.alphabet[(token[1]>>6)|(token[0]<<2)&encodingIdxMax]
+	dst[orderIdxs[2]] = e.alphabet[(token[1]>>1)&encodingIdxMax]
+	dst[orderIdxs[3]] = e.alphabet[(token[2]>>4)|(token[1]<<4)&encodingIdxMax]
+	dst[4] = e.alphabet[token[3]>>7|(token[2]<<1)&encodingIdxMax]
+	dst[8] = e.alphabet[(token[3]>>2)&encodingIdxMax]
+	dst[orderIdxs[4]] = e.alphabet[token[4]>>5|(token[3]<<3)&encodingIdxMax]
+	dst[orderIdxs[5]] = e.alphabet[token[4]&encodingIdxMax]
+	dst[orderIdxs[6]] = e.alphabet[token[5]>>3]
+	dst[12] = e.alphabet[(token[6]>>6)|(token[5]<<2)&encodingIdxMax]
+	dst[16] = e.alphabet[(token[6]>>1)&encodingIdxMax]
+	dst[orderIdxs[7]] = e.alphabet[(token[7]>>4)|(token[6]<<4)&encodingIdxMax]
+	dst[orderIdxs[8]] = e.alphabet[token[8]>>7|(token[7]<<1)&encodingIdxMax]
+	dst[20] = e.alphabet[(token[8]>>2)&encodingIdxMax]
+	dst[orderIdxs[9]] = e.alphabet[(token[9]>>5)|(token[8]<<3)&encodingIdxMax]
+	dst[24] = e.alphabet[token[9]&encodingIdxMax]
+	dst[orderIdxs[10]] = e.alphabet[token[10]>>3]
+	dst[28] = e.alphabet[(token[11]>>6)|(token[10]<<2)&encodingIdxMax]
+	dst[orderIdxs[11]] = e.alphabet[(token[11]>>1)&encodingIdxMax]
+	dst[29] = e.alphabet[(token[11]<<4)&encodingIdxMax]
+}
+
+// decode by unrolling the stdlib base32 algorithm + customized safe check.
+// The order chars are first resolved into the positions of the value chars,
+// then each value char is decoded with a single lookup.
+func (e *Encoding) decode(token *Token, src []byte) bool {
+	_ = src[encodedLen-1]
+	_ = token[rawLen-1]
+
+	pos, ok := positions(e, src)
+	if !ok {
+		return false
+	}
+	var v [len(orderPositions)]byte
+	for k, p := range pos {
+		v[k] = e.dec[src[p]]
+	}
+	token[11] = e.dec[src[28]]<<6 | v[11]<<1 | e.dec[src[29]]>>4
+	if e.alphabet[(token[11]<<4)&encodingIdxMax] != src[29] {
+		return false
+	}
+	token[10] = v[10]<<3 | e.dec[src[28]]>>2
+	token[9] = v[9]<<5 | e.dec[src[24]]
+
+	token[8] = v[8]<<7 | e.dec[src[20]]<<2 | v[9]>>3
+	token[7] = v[7]<<4 | v[8]>>1
+
+	token[6] = e.dec[src[12]]<<6 | e.dec[src[16]]<<1 | v[7]>>4
+	token[5] = v[6]<<3 | e.dec[src[12]]>>2
+	token[4] = v[4]<<5 | v[5]
+
+	token[3] = e.dec[src[4]]<<7 | e.dec[src[8]]<<2 | v[4]>>3
+	token[2] = v[3]<<4 | e.dec[src[4]]>>1
+	token[1] = v[1]<<6 | v[2]<<1 | v[3]>>4
+	token[0] = v[0]<<3 | v[1]>>2
+
+	return true
+}
+
+// isValuePosition reports whether a decoded order char is the position of a
+// value char, order chars pointing anywhere else are invalid.
+var isValuePosition = func() (t [encodingIdxMax + 1]bool) {
+	for _, p := range valuePositions {
+		t[p] = true
+	}
+	return t
+}()
+
+// positions resolves the order chars of src, an encoded token only made of
+// chars of the alphabet of e, into the positions of the value chars, in the
+// order of orderPositions. It reports false if one of them isn't a value position.
+func positions[T string | []byte](e *Encoding, src T) (pos [len(orderPositions)]byte, ok bool) {
+	_ = src[encodedLen-1]
+	for k, i := range orderPositions {
+		p := e.dec[src[i]]
+		if !isValuePosition[p&encodingIdxMax] {
+			return pos, false
+		}
+		pos[k] = p
+	}
+	return pos, true
+}
+
+// consistent reports whether the order and padding chars of src, an encoded
+// token only made of chars of the alphabet of e, are consistent. It's the check
+// decode applies.
+func consistent[T string | []byte](e *Encoding, src T) bool {
+	pos, ok := positions(e, src)
+	if !ok {
+		return false
+	}
+	// check the last byte
+	last := e.dec[src[28]]<<6 | e.dec[src[pos[11]]]<<1 | e.dec[src[29]]>>4
+	return e.alphabet[(last<<4)&encodingIdxMax] == src[29]
+}
@@ -0,0 +1,321 @@
+package xtoken
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/bits"
+	mathrand "math/rand"
+	randv2 "math/rand/v2"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	// noLookAlikes has no 0/O and 1/l/I.
+	noLookAlikes = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789=+*!~.:"
+	// noDashes is the standard alphabet with '.' and '~' instead of '-' and '_'.
+	noDashes = "aAbBcCdDeEfFgGhHiIjJkKlLmMnNoOpPqQrRsStTuUvVwWxXyYzZ0123456789.~"
+)
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+func TestNewEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet string
+		wantErr  bool
+	}{
+		{"std", encoding, false},
+		{"no look-alikes", noLookAlikes, false},
+		{"no dashes", noDashes, false},
+		{"empty", "", true},
+		{"too short", encoding[:63], true},
+		{"too long", encoding + "!", true},
+		{"duplicate", "a" + encoding[1:63] + "a", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEncoding(tt.alphabet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEncoding() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && e != nil {
+				t.Errorf("NewEncoding() = %v, want nil on error", e)
+			}
+		})
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	for _, alphabet := range []string{encoding, noLookAlikes, noDashes} {
+		e := mustNewEncoding(alphabet)
+		for i := 0; i < 1000; i++ {
+			token := New()
+			s := e.EncodeToString(token)
+			if len(s) != encodedLen {
+				t.Fatalf("EncodeToString() = %q, want %d chars", s, encodedLen)
+			}
+			for j := 0; j < len(s); j++ {
+				if strings.IndexByte(alphabet, s[j]) < 0 {
+					t.Fatalf("EncodeToString() = %q, %q is not in the alphabet %q", s, s[j], alphabet)
+				}
+			}
+			got, err := e.DecodeString(s)
+			if err != nil {
+				t.Fatalf("DecodeString(%q) err = %v", s, err)
+			}
+			if got != token {
+				t.Fatalf("DecodeString(%q) = %v, want %v", s, got, token)
+			}
+		}
+	}
+}
+
+func TestStdEncoding(t *testing.T) {
+	token := New()
+	got, err := FromString(StdEncoding.EncodeToString(token))
+	if err != nil || got != token {
+		t.Errorf("FromString(StdEncoding.EncodeToString()) = %v, %v, want %v", got, err, token)
+	}
+	got, err = StdEncoding.DecodeString(token.String())
+	if err != nil || got != token {
+		t.Errorf("StdEncoding.DecodeString(String()) = %v, %v, want %v", got, err, token)
+	}
+}
+
+func TestEncodingMismatch(t *testing.T) {
+	// The order chars of one alphabet point past the end of the encoded form
+	// under the reversed one, so decoding always fails.
+	rev := mustNewEncoding(reverse(encoding))
+	for i := 0; i < 1000; i++ {
+		s := New().String()
+		if got, err := rev.DecodeString(s); !errors.Is(err, ErrInvalidToken) || !got.IsZero() {
+			t.Fatalf("DecodeString(%q) = %v, %v, want the nil token and %v", s, got, err, ErrInvalidToken)
+		}
+	}
+
+	// Symbols missing from the alphabet are reported as invalid characters.
+	s := New().String()
+	s = s[:4] + "." + s[5:]
+	_, err := FromString(s)
+	var charErr *InvalidCharacterError
+	if !errors.As(err, &charErr) || charErr.Pos != 4 {
+		t.Errorf("FromString(%q) err = %v, want an *InvalidCharacterError at 4", s, err)
+	}
+	if _, err := mustNewEncoding(noDashes).DecodeString(strings.Repeat("-", encodedLen)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("DecodeString() err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+// referenceDecode is the previous implementation of decode, with chained
+// lookups of the order chars, kept to check the current one against it.
+func referenceDecode(e *Encoding, token *Token, src []byte) bool {
+	_ = src[encodedLen-1]
+	_ = token[rawLen-1]
+
+	if !referenceConsistent(e, src) {
+		return false
+	}
+
+	token[11] = e.dec[src[28]]<<6 | e.dec[src[e.dec[src[25]]]]<<1 | e.dec[src[29]]>>4
+	token[10] = e.dec[src[e.dec[src[14]]]]<<3 | e.dec[src[28]]>>2
+	token[9] = e.dec[src[e.dec[src[1]]]]<<5 | e.dec[src[24]]
+
+	token[8] = e.dec[src[e.dec[src[18]]]]<<7 | e.dec[src[20]]<<2 | e.dec[src[e.dec[src[1]]]]>>3
+	token[7] = e.dec[src[e.dec[src[10]]]]<<4 | e.dec[src[e.dec[src[18]]]]>>1
+
+	token[6] = e.dec[src[12]]<<6 | e.dec[src[16]]<<1 | e.dec[src[e.dec[src[10]]]]>>4
+	token[5] = e.dec[src[e.dec[src[26]]]]<<3 | e.dec[src[12]]>>2
+	token[4] = e.dec[src[e.dec[src[6]]]]<<5 | e.dec[src[e.dec[src[15]]]]
+	//
+	token[3] = e.dec[src[4]]<<7 | e.dec[src[8]]<<2 | e.dec[src[e.dec[src[6]]]]>>3
+	token[2] = e.dec[src[e.dec[src[30]]]]<<4 | e.dec[src[4]]>>1
+	token[1] = e.dec[src[e.dec[src[13]]]]<<6 | e.dec[src[e.dec[src[22]]]]<<1 | e.dec[src[e.dec[src[30]]]]>>4
+	token[0] = e.dec[src[e.dec[src[2]]]]<<3 | e.dec[src[e.dec[src[13]]]]>>2
+
+	return true
+}
+
+func referenceConsistent(e *Encoding, src []byte) bool {
+	_ = src[encodedLen-1]
+	// The order bytes index src, reject the ones pointing out of it.
+	for _, i := range orderPositions {
+		if e.dec[src[i]] >= encodedLen {
+			return false
+		}
+	}
+	// check the last byte
+	last := e.dec[src[28]]<<6 | e.dec[src[e.dec[src[25]]]]<<1 | e.dec[src[29]]>>4
+	return e.alphabet[(last<<4)&encodingIdxMax] == src[29]
+}
+
+func TestDecodeMatchesReference(t *testing.T) {
+	for _, e := range []*Encoding{StdEncoding, mustNewEncoding(noLookAlikes)} {
+		for i := 0; i < 100000; i++ {
+			var want Token
+			if _, err := rand.Read(want[:]); err != nil {
+				t.Fatal(err)
+			}
+			src := []byte(e.EncodeToString(want))
+			var got, ref Token
+			if !e.decode(&got, src) || !referenceDecode(e, &ref, src) {
+				t.Fatalf("decode(%q) failed", src)
+			}
+			if got != want || ref != want {
+				t.Fatalf("decode(%q) = %x, reference = %x, want %x", src, got[:], ref[:], want[:])
+			}
+		}
+	}
+}
+
+func TestDecodeRejectsNonValuePositions(t *testing.T) {
+	// The time order char points at a padding char, which the reference
+	// implementation decoded as a value char.
+	b := []byte(New().String())
+	b[2] = encoding[4]
+	if _, err := FromString(string(b)); !errors.Is(err, ErrInconsistentToken) {
+		t.Errorf("FromString(%q) err = %v, want %v", b, err, ErrInconsistentToken)
+	}
+}
+
+func FuzzDecodeString(f *testing.F) {
+	for _, v := range IDs {
+		f.Add(v.token.String())
+	}
+	f.Add("________________________________")
+	f.Add("--------------------------------")
+	f.Add("")
+	encodings := []*Encoding{StdEncoding, mustNewEncoding(noLookAlikes)}
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, e := range encodings {
+			token, err := e.DecodeString(s)
+			if err != nil {
+				kinds := 0
+				for _, kind := range []error{ErrBadLength, ErrBadChar, ErrBadChecksum} {
+					if errors.Is(err, kind) {
+						kinds++
+					}
+				}
+				if kinds != 1 || !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("DecodeString(%q) err = %v, want exactly one kind of ErrInvalidToken", s, err)
+				}
+				if !token.IsZero() {
+					t.Fatalf("DecodeString(%q) = %x on error, want nil token", s, token[:])
+				}
+				continue
+			}
+			if got, err := e.DecodeString(e.EncodeToString(token)); err != nil || got != token {
+				t.Fatalf("DecodeString(EncodeToString(%x)) = %x, %v", token[:], got[:], err)
+			}
+		}
+	})
+}
+
+func TestEncodeShuffle(t *testing.T) {
+	token := New()
+	const n = 20000
+	seen := make(map[[12]byte]struct{}, n)
+	// counts[k][p] is how many times the value k was stored at the p-th value position.
+	var counts [12][12]int
+	slot := make(map[int]int, len(valuePositions))
+	for p, v := range valuePositions {
+		slot[v] = p
+	}
+	for i := 0; i < n; i++ {
+		s := token.String()
+		pos, ok := positions(StdEncoding, s)
+		if !ok {
+			t.Fatalf("positions(%q) failed", s)
+		}
+		seen[pos] = struct{}{}
+		for k, p := range pos {
+			counts[k][slot[int(p)]]++
+		}
+	}
+	// There are 12! layouts, a repeat among n is unlikely.
+	if len(seen) < n-10 {
+		t.Errorf("%d distinct layouts out of %d", len(seen), n)
+	}
+	for k := range counts {
+		for p, c := range counts[k] {
+			// The expected count is n/12, about 1667 with a standard deviation of 39.
+			if c < n/12-300 || c > n/12+300 {
+				t.Errorf("value %d stored %d times at position %d, want about %d", k, c, valuePositions[p], n/12)
+			}
+		}
+	}
+}
+
+func TestEncodeConcurrent(t *testing.T) {
+	// The shuffle source is per thread, so parallel calls neither race nor
+	// share a layout sequence, run with -race.
+	token := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if got, err := FromString(token.String()); err != nil || got != token {
+					t.Errorf("FromString(String()) = %x, %v, want %x", got[:], err, token[:])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkEncodeToString(b *testing.B) {
+	e := mustNewEncoding(noLookAlikes)
+	token := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = e.EncodeToString(token)
+	}
+}
+
+// BenchmarkEncodeToStringParallel checks that the shuffle doesn't contend on a
+// shared source.
+func BenchmarkEncodeToStringParallel(b *testing.B) {
+	token := New()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = StdEncoding.EncodeToString(token)
+		}
+	})
+}
+
+// BenchmarkShuffleSourceParallel compares the shuffle of encode with the
+// math/rand Shuffle of the global source it replaced.
+func BenchmarkShuffleSourceParallel(b *testing.B) {
+	b.Run("source=math-rand", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			idxs := valuePositions
+			for pb.Next() {
+				mathrand.Shuffle(len(idxs), func(i, j int) { idxs[i], idxs[j] = idxs[j], idxs[i] })
+			}
+		})
+	})
+	b.Run("source=rand-v2", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			idxs := valuePositions
+			for pb.Next() {
+				x := randv2.Uint64()
+				for i := len(idxs) - 1; i > 0; i-- {
+					hi, lo := bits.Mul64(x, uint64(i+1))
+					x = lo
+					idxs[i], idxs[hi] = idxs[hi], idxs[i]
+				}
+			}
+		})
+	})
+}
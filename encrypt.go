@@ -0,0 +1,157 @@
+package xtoken
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+const (
+	gcmNonceSize = 12 // size of the nonce of AES-GCM
+	gcmTagSize   = 16 // size of the authentication tag of AES-GCM
+)
+
+const (
+	// ErrBadCiphertext is returned when the payload of a sealed Token doesn't
+	// decrypt with the key, e.g. it was tampered with.
+	ErrBadCiphertext strErr = "bad Token ciphertext"
+)
+
+// Encryptor seals small payloads, e.g. a user id and scopes, into opaque
+// tokens and opens them back, for stateless session tokens. It's safe for
+// concurrent use.
+//
+// A sealed token is the string representation of a new Token followed by a
+// random 12-byte nonce and the payload encrypted with AES-GCM, encoded with
+// the token alphabet. The token is authenticated along with the payload. It
+// isn't the nonce: tokens of different processes only differ by their machine
+// id and pid, which replicas sharing a key may have in common. With random
+// nonces, a key should seal less than 2^32 payloads, rotate it with a Keyring
+// well before.
+type Encryptor struct {
+	aead cipher.AEAD
+	ring *Keyring
+	ttl  time.Duration
+}
+
+// EncryptorOption configures an Encryptor.
+type EncryptorOption func(e *Encryptor)
+
+// WithEncryptorTTL makes Open reject the tokens older than ttl with
+// ErrExpired, taking their timestamp as their creation time. Tokens don't
+// expire by default.
+func WithEncryptorTTL(ttl time.Duration) EncryptorOption {
+	return func(e *Encryptor) {
+		e.ttl = ttl
+	}
+}
+
+// NewEncryptor returns an Encryptor with key configured with opts, the key
+// must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewEncryptor(key []byte, opts ...EncryptorOption) (*Encryptor, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &Encryptor{aead: aead}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// NewKeyringEncryptor returns an Encryptor with the keys of ring configured
+// with opts, the encrypted payloads are preceded by the 1-byte id of their
+// key. It returns an error when the current key of ring isn't a valid AES
+// key, Seal panics if a later one isn't.
+func NewKeyringEncryptor(ring *Keyring, opts ...EncryptorOption) (*Encryptor, error) {
+	if _, err := ring.currentKey().cipher(); err != nil {
+		return nil, err
+	}
+	e := &Encryptor{ring: ring}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// newGCM returns the AES-GCM cipher of key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal generates a globally unique Token and returns it followed by payload
+// encrypted, in 32+ceil((len(payload)+28)*4/3) chars, or
+// 32+ceil((len(payload)+29)*4/3) with a Keyring.
+func (e *Encryptor) Seal(payload []byte) string {
+	token := New()
+	aead := e.aead
+	sealed := make([]byte, 0, 1+gcmNonceSize+len(payload)+gcmTagSize)
+	if e.ring != nil {
+		key := e.ring.currentKey()
+		var err error
+		if aead, err = key.cipher(); err != nil {
+			panic(fmt.Sprintf("xtoken: key %d of the keyring: %v", key.id, err))
+		}
+		sealed = append(sealed, key.id)
+	}
+	n := len(sealed)
+	sealed = sealed[:n+gcmNonceSize]
+	if _, err := rand.Read(sealed[n:]); err != nil {
+		panic(fmt.Sprintf("xtoken: cannot generate nonce: %v", err))
+	}
+	sealed = aead.Seal(sealed, sealed[n:], payload, token[:])
+	text := make([]byte, encodedLen+signatureEncoding.EncodedLen(len(sealed)))
+	StdEncoding.encode(text, token[:])
+	signatureEncoding.Encode(text[encodedLen:], sealed)
+	return string(text)
+}
+
+// Open reads the Token and the payload of a string returned by Seal. It
+// returns ErrInvalidToken for malformed input, ErrBadCiphertext when the
+// payload doesn't decrypt with the key, ErrUnknownKey when its key isn't in
+// the Keyring of the Encryptor and ErrExpired when the token is older than the
+// ttl of the Encryptor.
+func (e *Encryptor) Open(s string) (Token, []byte, error) {
+	overhead := gcmNonceSize + gcmTagSize
+	if e.ring != nil {
+		overhead++ // the key id
+	}
+	if min := encodedLen + signatureEncoding.EncodedLen(overhead); len(s) < min {
+		return nilToken, nil, &InvalidLengthError{Got: len(s), Want: min}
+	}
+	token, err := FromString(s[:encodedLen])
+	if err != nil {
+		return nilToken, nil, err
+	}
+	sealed, err := signatureEncoding.DecodeString(s[encodedLen:])
+	if err != nil {
+		return nilToken, nil, ErrInvalidToken
+	}
+	aead := e.aead
+	if e.ring != nil {
+		key, err := e.ring.lookup(sealed[0])
+		if err != nil {
+			return nilToken, nil, err
+		}
+		if aead, err = key.cipher(); err != nil {
+			return nilToken, nil, ErrBadCiphertext
+		}
+		sealed = sealed[1:]
+	}
+	nonce, sealed := sealed[:gcmNonceSize], sealed[gcmNonceSize:]
+	payload, err := aead.Open(sealed[:0], nonce, sealed, token[:])
+	if err != nil {
+		return nilToken, nil, ErrBadCiphertext
+	}
+	if e.ttl > 0 && token.Expired(e.ttl) {
+		return nilToken, nil, ErrExpired
+	}
+	return token, payload, nil
+}
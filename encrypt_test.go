@@ -0,0 +1,109 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestEncryptor(t *testing.T, key string, opts ...EncryptorOption) *Encryptor {
+	t.Helper()
+	e, err := NewEncryptor([]byte(key), opts...)
+	if err != nil {
+		t.Fatalf("NewEncryptor() err: %v", err)
+	}
+	return e
+}
+
+func TestEncryptor(t *testing.T) {
+	e := newTestEncryptor(t, "0123456789abcdef")
+	for _, payload := range [][]byte{nil, []byte("user:42 scopes:read,write"), bytes.Repeat([]byte{0xFF}, 100)} {
+		s := e.Seal(payload)
+		token, got, err := e.Open(s)
+		if err != nil {
+			t.Fatalf("Open() err: %v", err)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Errorf("Open() payload = %q, want %q", got, payload)
+		}
+		if want, err := FromString(s[:encodedLen]); err != nil || token != want {
+			t.Errorf("Open() token = %v, want %v", token, want)
+		}
+		if bytes.Contains([]byte(s), payload) && len(payload) > 0 {
+			t.Errorf("Seal() = %q holds the payload in clear", s)
+		}
+	}
+	if a, b := e.Seal([]byte("same")), e.Seal([]byte("same")); a[encodedLen:] == b[encodedLen:] {
+		t.Errorf("Seal() encrypts the same payload twice to %q", a[encodedLen:])
+	}
+}
+
+func TestEncryptorNonce(t *testing.T) {
+	e := newTestEncryptor(t, "0123456789abcdef")
+	// Replicas with the same machine id and pid generate the same tokens, the
+	// nonces must differ all the same.
+	nonces := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		s := e.Seal([]byte("user:42"))
+		sealed, err := signatureEncoding.DecodeString(s[encodedLen:])
+		if err != nil {
+			t.Fatalf("DecodeString() err: %v", err)
+		}
+		token := MustFromString(s[:encodedLen])
+		nonce := string(sealed[:gcmNonceSize])
+		if nonce == string(token[:]) || nonces[nonce] {
+			t.Fatalf("Seal() = %q reuses nonce %x", s, nonce)
+		}
+		nonces[nonce] = true
+	}
+}
+
+func TestEncryptorTamper(t *testing.T) {
+	e := newTestEncryptor(t, "0123456789abcdef")
+	s := e.Seal([]byte("user:42"))
+	for i := 0; i < len(s); i++ {
+		b := []byte(s)
+		b[i] = encoding[(StdEncoding.dec[b[i]]+1)%64]
+		if _, _, err := e.Open(string(b)); err == nil {
+			t.Errorf("Open() of %q tampered at %d err = nil", b, i)
+		}
+	}
+	if _, _, err := e.Open(s[:encodedLen] + s[encodedLen+1:]); err == nil {
+		t.Errorf("Open() of a truncated ciphertext err = nil")
+	}
+
+	other := newTestEncryptor(t, "fedcba9876543210")
+	if _, _, err := other.Open(s); err != ErrBadCiphertext {
+		t.Errorf("Open() with another key err = %v, want %v", err, ErrBadCiphertext)
+	}
+	if _, _, err := e.Open(s[:40]); !errors.Is(err, ErrBadLength) {
+		t.Errorf("Open() of a short input err = %v, want %v", err, ErrBadLength)
+	}
+	if _, _, err := e.Open("not a token" + s[11:]); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Open() of a malformed token err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestEncryptorTTL(t *testing.T) {
+	e := newTestEncryptor(t, "0123456789abcdef", WithEncryptorTTL(time.Hour))
+	s := e.Seal([]byte("user:42"))
+	if _, _, err := e.Open(s); err != nil {
+		t.Fatalf("Open() err: %v", err)
+	}
+	withNow(t, time.Now().Add(2*time.Hour))
+	if _, _, err := e.Open(s); err != ErrExpired {
+		t.Errorf("Open() of an expired token err = %v, want %v", err, ErrExpired)
+	}
+}
+
+func TestNewEncryptorKeySize(t *testing.T) {
+	for _, n := range []int{16, 24, 32} {
+		if _, err := NewEncryptor(make([]byte, n)); err != nil {
+			t.Errorf("NewEncryptor() with a %d bytes key err: %v", n, err)
+		}
+	}
+	if _, err := NewEncryptor([]byte("short")); err == nil {
+		t.Error("NewEncryptor() with a 5 bytes key err = nil")
+	}
+}
@@ -0,0 +1,26 @@
+package xtoken
+
+import "time"
+
+// Epoch returns the epoch the Generator counts seconds from, see WithEpoch.
+func (g *Generator) Epoch() time.Time {
+	return time.Unix(g.epoch, 0)
+}
+
+// Expired reports whether the token generated by g is older than ttl as of the
+// clock of the Generator, like Token.Expired but taking its epoch into account.
+func (g *Generator) Expired(token Token, ttl time.Duration) bool {
+	return g.now().Sub(g.Time(token)) > ttl
+}
+
+// MinTokenForTime is like the package-level MinTokenForTime for the tokens
+// generated by g, taking its epoch into account.
+func (g *Generator) MinTokenForTime(t time.Time) Token {
+	return minTokenForSeconds(clampSeconds(t, g.epoch))
+}
+
+// MaxTokenForTime is like the package-level MaxTokenForTime for the tokens
+// generated by g, taking its epoch into account.
+func (g *Generator) MaxTokenForTime(t time.Time) Token {
+	return maxTokenForSeconds(clampSeconds(t, g.epoch))
+}
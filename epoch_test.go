@@ -0,0 +1,37 @@
+package xtoken
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGeneratorEpochHelpers(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: epoch.Add(48 * time.Hour)}
+	g := NewGenerator(WithEpoch(epoch), WithClock(clock.Now))
+	if got := g.Epoch(); !got.Equal(epoch) {
+		t.Errorf("Epoch() = %v, want %v", got, epoch)
+	}
+
+	token := g.New()
+	if g.Expired(token, time.Hour) {
+		t.Error("Expired() = true for a new token")
+	}
+	clock.Add(2 * time.Hour)
+	if !g.Expired(token, time.Hour) {
+		t.Error("Expired() = false for a token older than its ttl")
+	}
+	// Without the epoch, the token looks 54 years old.
+	if token.Time().Year() != 1970 {
+		t.Fatalf("Time() = %v, want a time in 1970", token.Time())
+	}
+
+	created := g.Time(token)
+	min, max := g.MinTokenForTime(created), g.MaxTokenForTime(created)
+	if token.Compare(min) < 0 || token.Compare(max) > 0 {
+		t.Errorf("token %x out of [%x, %x]", token[:], min[:], max[:])
+	}
+	if got := g.MinTokenForTime(epoch.Add(-time.Hour)); !got.IsZero() {
+		t.Errorf("MinTokenForTime() before the epoch = %x, want zero", got[:])
+	}
+}
@@ -0,0 +1,110 @@
+package xtoken
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// invalidErr allows declaring constant errors which satisfy
+// errors.Is(err, ErrInvalidToken).
+type invalidErr string
+
+func (err invalidErr) Error() string { return string(err) }
+
+func (err invalidErr) Is(target error) bool {
+	return target == ErrInvalidToken || target == ErrBadChecksum && (err == ErrInconsistentToken || err == ErrChecksum)
+}
+
+const (
+	// ErrInconsistentToken is returned when an encoded Token only has valid chars
+	// but its order or padding chars don't match its value chars. It satisfies
+	// errors.Is(err, ErrBadChecksum).
+	ErrInconsistentToken invalidErr = "invalid Token: inconsistent encoding"
+)
+
+// The kinds of malformed input, to tell them apart with errors.Is whatever the
+// concrete error returned.
+const (
+	// ErrBadLength is matched by *InvalidLengthError.
+	ErrBadLength invalidErr = "invalid Token: bad length"
+	// ErrBadChar is matched by *InvalidCharacterError.
+	ErrBadChar invalidErr = "invalid Token: bad character"
+	// ErrBadChecksum is matched by ErrInconsistentToken and ErrChecksum, when
+	// all the chars are valid but the redundant ones don't match the others.
+	ErrBadChecksum invalidErr = "invalid Token: bad checksum"
+)
+
+// InvalidLengthError is returned when an encoded Token doesn't have the expected length.
+// It satisfies errors.Is(err, ErrInvalidToken) and errors.Is(err, ErrBadLength).
+type InvalidLengthError struct {
+	Got  int // length of the input
+	Want int // expected length
+}
+
+func (e *InvalidLengthError) Error() string {
+	return fmt.Sprintf("invalid Token: length %d, want %d", e.Got, e.Want)
+}
+
+func (e *InvalidLengthError) Is(target error) bool {
+	return target == ErrInvalidToken || target == ErrBadLength
+}
+
+// InvalidCharacterError is returned when an encoded Token contains a char out
+// of its alphabet. It satisfies errors.Is(err, ErrInvalidToken) and
+// errors.Is(err, ErrBadChar).
+type InvalidCharacterError struct {
+	Char byte // offending char
+	Pos  int  // position of Char in the input
+}
+
+func (e *InvalidCharacterError) Error() string {
+	return fmt.Sprintf("invalid Token: invalid character %s at position %d", strconv.Quote(string([]byte{e.Char})), e.Pos)
+}
+
+func (e *InvalidCharacterError) Is(target error) bool {
+	return target == ErrInvalidToken || target == ErrBadChar
+}
+
+// TimeOutOfRangeError is returned when a time can't be stored in the 4-byte
+// timestamp. It satisfies errors.Is(err, ErrTimeOutOfRange).
+type TimeOutOfRangeError struct {
+	Time time.Time // offending time
+	Min  time.Time // first representable time
+	Max  time.Time // last representable time
+}
+
+func (e *TimeOutOfRangeError) Error() string {
+	return fmt.Sprintf("time %s out of range of Token [%s, %s]",
+		e.Time.Format(time.RFC3339), e.Min.UTC().Format(time.RFC3339), e.Max.UTC().Format(time.RFC3339))
+}
+
+func (e *TimeOutOfRangeError) Is(target error) bool { return target == ErrTimeOutOfRange }
+
+// checkedSeconds returns the timestamp stored for t, counting seconds from
+// epoch, it fails when t is out of range.
+func checkedSeconds(t time.Time, epoch int64) (uint32, error) {
+	secs := t.Unix() - epoch
+	if secs < 0 || secs > 1<<32-1 {
+		return 0, &TimeOutOfRangeError{
+			Time: t,
+			Min:  time.Unix(epoch, 0),
+			Max:  time.Unix(epoch+1<<32-1, 0),
+		}
+	}
+	return uint32(secs), nil
+}
+
+// checkText returns an error unless text is n chars long and only contains
+// chars of the alphabet of the decoding map dec.
+func checkText[T string | []byte](text T, n int, dec *[256]byte) error {
+	if len(text) != n {
+		return &InvalidLengthError{Got: len(text), Want: n}
+	}
+	for i := 0; i < len(text); i++ {
+		if dec[text[i]] == 0xFF {
+			return &InvalidCharacterError{Char: text[i], Pos: i}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package xtoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	valid := IDs[0].token.String()
+
+	_, err := FromString(valid[:31])
+	var lengthErr *InvalidLengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("FromString() err = %v, want an *InvalidLengthError", err)
+	}
+	if lengthErr.Got != 31 || lengthErr.Want != encodedLen {
+		t.Errorf("InvalidLengthError = %+v, want Got 31, Want %d", lengthErr, encodedLen)
+	}
+	if !strings.Contains(err.Error(), "31") || !strings.Contains(err.Error(), "32") {
+		t.Errorf("Error() = %q, want the lengths", err.Error())
+	}
+
+	_, err = FromString(valid[:7] + "!" + valid[8:])
+	var charErr *InvalidCharacterError
+	if !errors.As(err, &charErr) {
+		t.Fatalf("FromString() err = %v, want an *InvalidCharacterError", err)
+	}
+	if charErr.Char != '!' || charErr.Pos != 7 {
+		t.Errorf("InvalidCharacterError = %+v, want Char '!', Pos 7", charErr)
+	}
+	if !strings.Contains(err.Error(), `"!"`) || !strings.Contains(err.Error(), "position 7") {
+		t.Errorf("Error() = %q, want the char and its position", err.Error())
+	}
+
+	var token Token
+	err = token.UnmarshalText([]byte("________________________________"))
+	if err != ErrInconsistentToken {
+		t.Errorf("UnmarshalText() err = %v, want %v", err, ErrInconsistentToken)
+	}
+
+	for _, err := range []error{lengthErr, charErr, ErrInconsistentToken} {
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("errors.Is(%v, ErrInvalidToken) = false, want true", err)
+		}
+	}
+	if errors.Is(ErrInconsistentToken, ErrBadSignature) {
+		t.Errorf("errors.Is(ErrInconsistentToken, ErrBadSignature) = true, want false")
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	valid := IDs[0].token.String()
+	tests := []struct {
+		name  string
+		parse func() error
+		kind  error
+	}{
+		{"length", func() error { _, err := FromString(valid[:31]); return err }, ErrBadLength},
+		{"char", func() error { _, err := FromString(valid[:7] + "!" + valid[8:]); return err }, ErrBadChar},
+		{"inconsistent", func() error { _, err := FromString("________________________________"); return err }, ErrBadChecksum},
+		{"checksum", func() error {
+			s := IDs[0].token.StringChecked()
+			c := encoding[(strings.IndexByte(encoding, s[encodedLen+1])+1)&encodingIdxMax]
+			_, err := ParseChecked(s[:encodedLen+1] + string(c))
+			return err
+		}, ErrBadChecksum},
+	}
+	kinds := []error{ErrBadLength, ErrBadChar, ErrBadChecksum}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse()
+			for _, kind := range kinds {
+				if got, want := errors.Is(err, kind), kind == tt.kind; got != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", err, kind, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package xtoken
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// ErrExpired is returned by Verifier.Verify when a token is older than its ttl.
+	ErrExpired strErr = "Token expired"
+)
+
+// timeNow returns the current time for Age, Expired and ParseStrict, tests
+// replace it.
+var timeNow = time.Now
+
+// Unix returns the timestamp part of the token as seconds since the Unix epoch,
+// without building a time.Time. Like Time, it assumes the default Unix epoch.
+func (token Token) Unix() int64 {
+	// First 4 bytes of ObjectId is 32-bit big-endian seconds from epoch.
+	return int64(binary.BigEndian.Uint32(token[0:4]))
+}
+
+// UnixMilli returns the timestamp part of the token as milliseconds since the
+// Unix epoch, the token only has a second resolution.
+func (token Token) UnixMilli() int64 {
+	return token.Unix() * 1000
+}
+
+// Age returns the time elapsed since the timestamp of the token, it's negative
+// when the timestamp is in the future.
+func (token Token) Age() time.Duration {
+	return token.ageAt(timeNow())
+}
+
+// Expired reports whether the token is older than ttl, taking its timestamp as
+// its creation time.
+func (token Token) Expired(ttl time.Duration) bool {
+	return token.ExpiredAt(ttl, timeNow())
+}
+
+// ExpiredAt reports whether the token is older than ttl as of asOf, taking its
+// timestamp as its creation time.
+func (token Token) ExpiredAt(ttl time.Duration, asOf time.Time) bool {
+	return token.ageAt(asOf) > ttl
+}
+
+// ExpiresAt returns the time the token expires with ttl, taking its timestamp
+// as its creation time.
+func (token Token) ExpiresAt(ttl time.Duration) time.Time {
+	return token.Time().Add(ttl)
+}
+
+// Verifier parses tokens and rejects the expired ones, so that the ttl of a
+// kind of tokens is enforced in a single place. It's safe for concurrent use.
+type Verifier struct {
+	ttl  time.Duration
+	opts []ParseOption
+}
+
+// NewVerifier returns a Verifier of tokens valid for ttl after their
+// timestamp, opts apply the checks of ParseStrict on top, e.g. MaxClockSkew.
+func NewVerifier(ttl time.Duration, opts ...ParseOption) *Verifier {
+	return &Verifier{ttl: ttl, opts: opts}
+}
+
+// Verify reads a Token from its string representation, it returns ErrExpired
+// when the token is older than the ttl of the Verifier and the errors of
+// ParseStrict otherwise.
+func (v *Verifier) Verify(s string) (Token, error) {
+	token, err := ParseStrict(s, v.opts...)
+	if err != nil {
+		return token, err
+	}
+	if token.Expired(v.ttl) {
+		return nilToken, ErrExpired
+	}
+	return token, nil
+}
+
+func (token Token) ageAt(asOf time.Time) time.Duration {
+	return asOf.Sub(time.Unix(token.Unix(), 0))
+}
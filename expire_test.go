@@ -0,0 +1,111 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// withNow makes timeNow return now until the end of the test.
+func withNow(t *testing.T, now time.Time) {
+	t.Helper()
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+}
+
+func TestUnix(t *testing.T) {
+	for _, v := range IDs {
+		if got := v.token.Unix(); got != v.timestamp {
+			t.Errorf("Unix() = %d, want %d", got, v.timestamp)
+		}
+		if got, want := v.token.UnixMilli(), v.token.Time().UnixMilli(); got != want {
+			t.Errorf("UnixMilli() = %d, want %d", got, want)
+		}
+	}
+	max := Token{0xff, 0xff, 0xff, 0xff}
+	if got, want := max.Unix(), int64(1<<32-1); got != want {
+		t.Errorf("Unix() = %d, want %d", got, want)
+	}
+}
+
+func TestAge(t *testing.T) {
+	withNow(t, time.Unix(IDs[0].timestamp, 0).Add(90*time.Minute))
+	tests := []struct {
+		name  string
+		token Token
+		want  time.Duration
+	}{
+		{"IDs[0]", IDs[0].token, 90 * time.Minute},
+		{"zero", nilToken, time.Duration(IDs[0].timestamp)*time.Second + 90*time.Minute},
+		{"future", NewWithTime(time.Unix(IDs[0].timestamp, 0).Add(2 * time.Hour)), -30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.token.Age(); got != tt.want {
+			t.Errorf("%s: Age() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpired(t *testing.T) {
+	created := time.Unix(IDs[0].timestamp, 0)
+	withNow(t, created.Add(time.Hour))
+	future := NewWithTime(created.Add(2 * time.Hour))
+	tests := []struct {
+		name  string
+		token Token
+		ttl   time.Duration
+		want  bool
+	}{
+		{"younger", IDs[0].token, 2 * time.Hour, false},
+		{"exactly ttl", IDs[0].token, time.Hour, false},
+		{"older", IDs[0].token, time.Hour - time.Second, true},
+		{"zero", nilToken, 24 * time.Hour, true},
+		{"future", future, 0, false},
+	}
+	for _, tt := range tests {
+		if got := tt.token.Expired(tt.ttl); got != tt.want {
+			t.Errorf("%s: Expired(%v) = %v, want %v", tt.name, tt.ttl, got, tt.want)
+		}
+		if got := tt.token.ExpiredAt(tt.ttl, created.Add(time.Hour)); got != tt.want {
+			t.Errorf("%s: ExpiredAt(%v) = %v, want %v", tt.name, tt.ttl, got, tt.want)
+		}
+	}
+	if !future.ExpiredAt(time.Hour, created.Add(4*time.Hour)) {
+		t.Error("ExpiredAt() = false once the future token is older than ttl")
+	}
+}
+
+func TestExpiresAt(t *testing.T) {
+	created := time.Unix(IDs[0].timestamp, 0)
+	if got, want := IDs[0].token.ExpiresAt(time.Hour), created.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt() = %v, want %v", got, want)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	withNow(t, created.Add(time.Hour))
+	v := NewVerifier(time.Hour, MaxClockSkew(time.Minute))
+	tests := []struct {
+		name    string
+		token   Token
+		wantErr error
+	}{
+		{"fresh", NewWithTime(created.Add(30 * time.Minute)), nil},
+		{"exactly ttl", NewWithTime(created), nil},
+		{"expired", NewWithTime(created.Add(-time.Second)), ErrExpired},
+		{"future", NewWithTime(created.Add(2 * time.Hour)), ErrTimeInFuture},
+	}
+	for _, tt := range tests {
+		got, err := v.Verify(tt.token.String())
+		if err != tt.wantErr {
+			t.Errorf("%s: Verify() err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && got != tt.token {
+			t.Errorf("%s: Verify() = %v, want %v", tt.name, got, tt.token)
+		}
+	}
+	if _, err := v.Verify("invalid"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,38 @@
+package xtoken
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format implements fmt.Formatter:
+//   - %s and %v print the string representation,
+//   - %q prints it double-quoted,
+//   - %x and %X print the 12 raw bytes as hex,
+//   - %+v prints the decomposed token, e.g.
+//     {time: 2011-03-22T17:50:19Z, machine: 60f486, pid: 58408, counter: 4271561}.
+//
+// Flags, width and precision are ignored.
+func (token Token) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		_, _ = io.WriteString(f, token.String())
+	case 'v':
+		if f.Flag('+') {
+			_, _ = fmt.Fprintf(f, "{time: %s, machine: %x, pid: %d, counter: %d}",
+				token.Time().UTC().Format(time.RFC3339), token.Machine(), token.Pid(), token.Counter())
+			return
+		}
+		_, _ = io.WriteString(f, token.String())
+	case 'q':
+		_, _ = io.WriteString(f, strconv.Quote(token.String()))
+	case 'x':
+		_, _ = fmt.Fprintf(f, "%x", token[:])
+	case 'X':
+		_, _ = fmt.Fprintf(f, "%X", token[:])
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(xtoken.Token=%s)", verb, token.String())
+	}
+}
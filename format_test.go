@@ -0,0 +1,64 @@
+package xtoken
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	token := IDs[0].token
+	parse := func(t *testing.T, s string) {
+		t.Helper()
+		got, err := FromString(s)
+		if err != nil {
+			t.Fatalf("FromString(%q) err: %v", s, err)
+		}
+		if got != token {
+			t.Errorf("FromString(%q) = %x, want %x", s, got[:], token[:])
+		}
+	}
+
+	for _, format := range []string{"%s", "%v", "%10s", "%-40v", "%.3s"} {
+		s := fmt.Sprintf(format, token)
+		if len(s) != encodedLen {
+			t.Errorf("Sprintf(%q) = %q, want the %d chars encoding", format, s, encodedLen)
+			continue
+		}
+		parse(t, s)
+	}
+
+	q := fmt.Sprintf("%q", token)
+	s, err := strconv.Unquote(q)
+	if err != nil {
+		t.Fatalf("Sprintf(%%q) = %s, not quoted: %v", q, err)
+	}
+	parse(t, s)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%x", "4d88e15b60f486e428412dc9"},
+		{"%X", "4D88E15B60F486E428412DC9"},
+		{"%30x", "4d88e15b60f486e428412dc9"},
+		{"%+v", "{time: 2011-03-22T17:50:19Z, machine: 60f486, pid: 58408, counter: 4271561}"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, token); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUnknownVerb(t *testing.T) {
+	got := fmt.Sprintf("%d", nilToken)
+	want := "%!d(xtoken.Token="
+	if len(got) != len(want)+encodedLen+1 || got[:len(want)] != want || got[len(got)-1] != ')' {
+		t.Fatalf("Sprintf(%%d) = %q, want %s…)", got, want)
+	}
+	parse, err := FromString(got[len(want) : len(got)-1])
+	if err != nil || !parse.IsZero() {
+		t.Errorf("Sprintf(%%d) = %q, want the nil token", got)
+	}
+}
@@ -0,0 +1,533 @@
+package xtoken
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Generator generates tokens with its own configuration, the package-level
+// New and NewWithTime use a default Generator.
+type Generator struct {
+	// last is the last timestamp issued in monotonic mode, -1 before the first one.
+	// It's accessed atomically, keep it first for 64-bit alignment on 32-bit platforms.
+	last int64
+
+	// rng is the state of the splitmix64 sequence drawing random counter steps,
+	// it's accessed atomically.
+	rng uint64
+
+	// issued is the timestamp in the high 32 bits and the number of tokens
+	// issued in that second in the low ones, when limit is set. It's accessed
+	// atomically.
+	issued uint64
+
+	// window holds the timestamp of the current second in its high 32 bits and
+	// the first counter value the Generator used in that second in its low 32
+	// bits, it lets nextCounter detect the counter wrapping within one second.
+	// It's per Generator since Generators with different epochs or monotonic
+	// timestamps don't agree on the current second. It's accessed atomically.
+	window uint64
+
+	// generation is the counterGeneration window was opened in, it's accessed
+	// atomically.
+	generation uint32
+
+	// epoch is subtracted from the Unix seconds before they are stored in the token.
+	epoch int64
+
+	// now returns the current time.
+	now func() time.Time
+
+	// monotonic prevents the timestamp from going backwards.
+	monotonic bool
+
+	// maxStep is the upper bound of the random counter step, 0 or 1 disable it.
+	maxStep uint32
+
+	// overflow is what New does when the counter would wrap within one second.
+	overflow OverflowPolicy
+
+	// blocks caches per-P *counterBlock when the sharded counter is enabled.
+	blocks *sync.Pool
+
+	// counter is incremented for every token, it points to objectIDCounter
+	// unless set by WithCounter.
+	counter *uint32
+
+	// machine overrides the package-level machine id when it's not nil.
+	machine *[4]byte
+
+	// pid overrides the package-level process id when hasPid is set.
+	pid    uint16
+	hasPid bool
+
+	// rand seeds the random counter step, readRand is used when it's nil.
+	rand io.Reader
+
+	// pepper obfuscates the tokens when it's not nil.
+	pepper *Pepper
+
+	// limit is the maximum number of tokens issued per second, 0 for no limit.
+	limit uint32
+}
+
+// Option configures a Generator.
+type Option func(g *Generator)
+
+// WithEpoch sets the epoch the 4-byte timestamp counts seconds from, it defaults
+// to the Unix epoch.
+//
+// A recent epoch pushes the wrap of the timestamp from 2106 to 136 years after it.
+// Tokens generated with a custom epoch carry no trace of it, so their time must
+// be read with Generator.Time of a Generator using the same epoch.
+func WithEpoch(epoch time.Time) Option {
+	return func(g *Generator) {
+		g.epoch = epoch.Unix()
+	}
+}
+
+// WithClock sets the function the Generator reads the current time from, it
+// defaults to time.Now. When New has to wait for the next second, it sleeps
+// until then and keeps waiting for as long as the clock reads the same time,
+// e.g. a frozen clock in tests: NewE returns ErrClockStalled instead.
+func WithClock(now func() time.Time) Option {
+	return func(g *Generator) {
+		g.now = now
+	}
+}
+
+// WithMonotonic makes New and NewStrict never issue a timestamp lower than the
+// previous one: when the clock steps backwards, the last timestamp is reused
+// and the counter keeps incrementing, so tokens stay in creation order.
+// When the counter would wrap within that timestamp, or its 3 bytes would roll
+// over to 0, the Generator moves on to the next second instead of waiting for
+// the clock to catch up.
+func WithMonotonic() Option {
+	return func(g *Generator) {
+		g.monotonic = true
+	}
+}
+
+// WithRandomCounterStep makes the counter advance by a random amount in
+// [1, maxStep] for every token instead of exactly 1, so that the difference
+// between the counters of two tokens doesn't reveal how many tokens were issued
+// in between. A larger maxStep means fewer tokens per second before the counter
+// would wrap: about 1<<25/(maxStep+1) on average.
+func WithRandomCounterStep(maxStep uint32) Option {
+	return func(g *Generator) {
+		g.maxStep = maxStep
+	}
+}
+
+// OverflowPolicy is what New and NewE do when more than 1<<24 tokens are
+// requested within one second, so that the counter would wrap and repeat a
+// token. NewStrict returns ErrCounterOverflow instead whatever the policy.
+type OverflowPolicy int
+
+const (
+	// OverflowWait makes New wait for the next second, or move on to it right
+	// away in monotonic mode, see WithMonotonic. It's the default.
+	OverflowWait OverflowPolicy = iota
+	// OverflowRandom makes New return a token of NewRandom instead, with the
+	// current timestamp: its 64 random bits make a collision unlikely, but it
+	// doesn't carry the machine id, pid and counter. It doesn't apply in
+	// monotonic mode, where a random token would break the order of the
+	// tokens: New moves on to the next second as with OverflowWait.
+	OverflowRandom
+	// OverflowError makes NewE return ErrCounterOverflow, for the callers
+	// which would rather fail than wait. New can't return an error, it waits
+	// as with OverflowWait.
+	OverflowError
+)
+
+// WithOverflowPolicy sets what New does when the counter would wrap within one
+// second, it defaults to OverflowWait. NewBatch and WithShardedCounter always
+// wait. Every overflow is counted in GeneratorStats.CounterOverflows and
+// reported to the MetricsHook.
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return func(g *Generator) {
+		g.overflow = p
+	}
+}
+
+// WithShardedCounter makes New take counter values from blocks reserved from
+// the shared counter instead of incrementing it for every token, which avoids
+// contention on it when New is called from many goroutines in parallel.
+// Tokens stay unique, but consecutive tokens don't necessarily have consecutive
+// counters, and some counter values are skipped when a block is dropped.
+func WithShardedCounter() Option {
+	return func(g *Generator) {
+		g.blocks = &sync.Pool{
+			New: func() interface{} { return new(counterBlock) },
+		}
+	}
+}
+
+// WithCounter gives the Generator its own counter, starting after start,
+// instead of sharing the package-level one, e.g. to run independent streams of
+// tokens or get reproducible tokens in tests.
+// Generators with their own counters may issue the same token, unless they are
+// told apart by their pid (see WithPid) or the tags of their tokens.
+func WithCounter(start uint32) Option {
+	return func(g *Generator) {
+		g.counter = &start
+	}
+}
+
+// WithPid sets the process id embedded in the tokens, it defaults to the
+// package-level one, see SetPid.
+func WithPid(p uint16) Option {
+	return func(g *Generator) {
+		g.pid, g.hasPid = p, true
+	}
+}
+
+// WithRandReader sets the source of randomness of the Generator, it defaults
+// to the package-level one, see SetRandReader.
+func WithRandReader(r io.Reader) Option {
+	return func(g *Generator) {
+		g.rand = r
+	}
+}
+
+// WithMaxPerSecond caps the tokens issued within one second to n, to throttle
+// token issuance: New, NewE, NewTagged and NewBatch wait for the next second
+// of the clock, see WithClock, and NewStrict returns ErrRateLimited, rather
+// than exceed it. Tokens are counted in the second they are stamped with. With n at most
+// 1<<24, the counter never wraps. NewWithTime isn't capped, and 0, the
+// default, disables the cap.
+func WithMaxPerSecond(n uint32) Option {
+	return func(g *Generator) {
+		g.limit = n
+	}
+}
+
+// WithPepper makes the Generator obfuscate the machine id, pid and counter of
+// its tokens with p, see Pepper. Use p.Reveal before reading them back.
+func WithPepper(p *Pepper) Option {
+	return func(g *Generator) {
+		g.pepper = p
+	}
+}
+
+// defaultGenerator is used by the package-level New* functions.
+var defaultGenerator = NewGenerator()
+
+// NewGenerator returns a Generator configured with opts, it panics if the
+// source of randomness fails, see NewGeneratorE.
+func NewGenerator(opts ...Option) *Generator {
+	g, err := NewGeneratorE(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// NewGeneratorE is like NewGenerator but returns an error when the source of
+// randomness set by SetRandReader fails.
+func NewGeneratorE(opts ...Option) (*Generator, error) {
+	g := &Generator{
+		last:    -1,
+		now:     time.Now,
+		counter: &objectIDCounter,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.maxStep > 1 {
+		var b [8]byte
+		if err := g.readRand(b[:]); err != nil {
+			return nil, fmt.Errorf("xtoken: cannot seed the counter step: %w", err)
+		}
+		g.rng = binary.BigEndian.Uint64(b[:])
+	}
+	return g, nil
+}
+
+// readRand fills b from the source of randomness of the Generator.
+func (g *Generator) readRand(b []byte) error {
+	if g.rand == nil {
+		return readRand(b)
+	}
+	_, err := io.ReadFull(g.rand, b)
+	return err
+}
+
+// step returns the amount to advance the counter by for the next token.
+func (g *Generator) step() uint32 {
+	if g.maxStep <= 1 {
+		return 1
+	}
+	// splitmix64, see https://prng.di.unimi.it/splitmix64.c
+	x := atomic.AddUint64(&g.rng, 0x9E3779B97F4A7C15)
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	x ^= x >> 31
+	// Map the high 32 bits to [0, maxStep) without a division.
+	return 1 + uint32((x>>32)*uint64(g.maxStep)>>32)
+}
+
+// New generates a globally unique Token.
+// If more than 1<<24 tokens are generated within one second, the counter would
+// wrap and repeat a previous token, so New waits for the next second instead.
+// It blocks for as long as the clock of the Generator doesn't reach it, see
+// WithClock, use NewE to get ErrClockStalled instead.
+func (g *Generator) New() Token {
+	generated(1)
+	policy := g.overflow
+	if policy == OverflowError {
+		policy = OverflowWait
+	}
+	for {
+		// The only error is ErrClockStalled, keep waiting for the clock.
+		token, err := g.next(policy)
+		if err == nil {
+			return token
+		}
+	}
+}
+
+// NewE is like New but returns ErrClockStalled instead of blocking when it
+// has to wait for the next second and the clock of the Generator doesn't
+// advance meanwhile, e.g. a frozen clock in tests. With OverflowError, see
+// WithOverflowPolicy, it returns ErrCounterOverflow instead of waiting when
+// the counter would wrap.
+func (g *Generator) NewE() (Token, error) {
+	token, err := g.next(g.overflow)
+	if err != nil {
+		return nilToken, err
+	}
+	generated(1)
+	return token, nil
+}
+
+// next generates a token with the overflow policy.
+func (g *Generator) next(policy OverflowPolicy) (Token, error) {
+	if g.blocks != nil {
+		return g.newSharded()
+	}
+	secs, i, err := g.reserve(1, g.step(), policy)
+	if err == errRandomToken {
+		return randomToken(secs), nil
+	}
+	if err != nil {
+		return nilToken, err
+	}
+	return g.newToken(secs, i), nil
+}
+
+// NewString is like New().String(), for the callers which only need the
+// string: the token is encoded straight into the memory of the returned
+// string, which saves String the copy of its buffer.
+func (g *Generator) NewString() string {
+	token := g.New()
+	text := make([]byte, encodedLen)
+	StdEncoding.encode(text, token[:])
+	// text is never written again, the string can own it.
+	return unsafe.String(unsafe.SliceData(text), encodedLen)
+}
+
+// counterBlockSize is the number of counter values reserved at once by the sharded counter.
+const counterBlockSize = 256
+
+// counterBlock is a range of the counter reserved for the timestamp secs, next
+// is the last value handed out and last the end of the range, both inclusive.
+type counterBlock struct {
+	secs, next, last uint32
+}
+
+func (g *Generator) newSharded() (Token, error) {
+	for {
+		now := g.now()
+		secs := g.seconds(now)
+		step := g.step()
+		b := g.blocks.Get().(*counterBlock)
+		// Values reserved for a second are only unique within it, so a block of
+		// another second is dropped.
+		if b.secs != secs || b.last-b.next < step {
+			size := counterBlockSize * step
+			if g.maxStep > 1 {
+				size = counterBlockSize * g.maxStep
+			}
+			var err error
+			// The tokens of the block are admitted one by one below.
+			if b.secs, b.last, err = g.reserve(0, size, OverflowWait); err != nil {
+				return nilToken, err
+			}
+			b.next = b.last - size
+		}
+		if g.limit > 0 && !g.admit(b.secs, 1) {
+			g.blocks.Put(b)
+			if err := g.waitNextSecond(now); err != nil {
+				return nilToken, err
+			}
+			continue
+		}
+		b.next += step
+		token := g.newToken(b.secs, b.next)
+		g.blocks.Put(b)
+		return token, nil
+	}
+}
+
+// errRandomToken is returned by reserve when the policy is OverflowRandom and
+// the counter would wrap.
+const errRandomToken strErr = "Token counter overflow, random token"
+
+// reserve counts tokens against the cap of the current second, see
+// WithMaxPerSecond, advances the counter by n and returns the timestamp and
+// the last counter value of the reserved range, so that the tokens are counted
+// in the second they are stamped with. When the cap is reached or the counter
+// would wrap within the current second, it waits for the next one unless
+// policy says otherwise, and returns ErrClockStalled when the clock doesn't
+// advance. n must be less than 1<<24.
+func (g *Generator) reserve(tokens, n uint32, policy OverflowPolicy) (uint32, uint32, error) {
+	for {
+		now := g.now()
+		secs := g.seconds(now)
+		if g.limit > 0 && tokens > 0 && !g.admit(secs, tokens) {
+			if err := g.waitNextSecond(now); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+		i, ok := g.nextCounter(secs, n)
+		// In monotonic mode, the range must not cross the wrap of the 3-byte
+		// counter either, or the tokens after it would sort first.
+		if ok && !(g.monotonic && (i-n)>>24 != i>>24) {
+			return secs, i, nil
+		}
+		if !ok {
+			counterWrapped()
+			switch {
+			case policy == OverflowError:
+				return secs, 0, ErrCounterOverflow
+			case policy == OverflowRandom && !g.monotonic:
+				return secs, 0, errRandomToken
+			}
+		}
+		if g.monotonic {
+			atomic.CompareAndSwapInt64(&g.last, int64(secs), int64(secs)+1)
+			continue
+		}
+		if err := g.waitNextSecond(now); err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+// waitNextSecond sleeps until the second after now on the clock of the
+// Generator, it returns ErrClockStalled when the clock doesn't advance
+// meanwhile rather than wait forever.
+func (g *Generator) waitNextSecond(now time.Time) error {
+	time.Sleep(now.Truncate(time.Second).Add(time.Second).Sub(now))
+	if g.now().Equal(now) {
+		return ErrClockStalled
+	}
+	return nil
+}
+
+// admit counts n more tokens issued in the second secs, it reports false
+// rather than exceed the cap of the Generator. When the clock steps backwards,
+// the tokens are counted in the latest second instead, so that going back and
+// forth doesn't reset the count.
+func (g *Generator) admit(secs, n uint32) bool {
+	for {
+		w := atomic.LoadUint64(&g.issued)
+		latest, count := uint32(w>>32), uint32(w)
+		if secs > latest {
+			latest, count = secs, 0
+		}
+		if uint64(count)+uint64(n) > uint64(g.limit) {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&g.issued, w, uint64(latest)<<32|uint64(count+n)) {
+			return true
+		}
+	}
+}
+
+// NewStrict is like New but returns ErrCounterOverflow instead of waiting when
+// the counter would wrap within the current second, or ErrRateLimited when
+// the Generator issued WithMaxPerSecond tokens in it. In monotonic mode, it
+// also returns ErrCounterOverflow when the 3 bytes of the counter roll over to
+// 0, and the next call moves on to the next second, see WithMonotonic.
+func (g *Generator) NewStrict() (Token, error) {
+	return g.newStrict(g.seconds(g.now()))
+}
+
+func (g *Generator) newStrict(secs uint32) (Token, error) {
+	if g.limit > 0 && !g.admit(secs, 1) {
+		return nilToken, ErrRateLimited
+	}
+	n := g.step()
+	i, ok := g.nextCounter(secs, n)
+	if !ok {
+		counterWrapped()
+		return nilToken, ErrCounterOverflow
+	}
+	if g.monotonic && (i-n)>>24 != i>>24 {
+		// The token would sort before the previous ones of secs.
+		atomic.CompareAndSwapInt64(&g.last, int64(secs), int64(secs)+1)
+		return nilToken, ErrCounterOverflow
+	}
+	generated(1)
+	return g.newToken(secs, i), nil
+}
+
+// seconds returns the timestamp to store for the current time now, taking the
+// monotonic mode into account.
+func (g *Generator) seconds(now time.Time) uint32 {
+	secs := now.Unix() - g.epoch
+	if !g.monotonic {
+		return uint32(secs)
+	}
+	for {
+		last := atomic.LoadInt64(&g.last)
+		if secs <= last {
+			return uint32(last)
+		}
+		if atomic.CompareAndSwapInt64(&g.last, last, secs) {
+			return uint32(secs)
+		}
+	}
+}
+
+// NewWithTime generates a globally unique Token with the passed in time.
+// Times outside of the range of the Generator silently wrap, use NewWithTimeE
+// to get an error instead. Since the time is chosen by the caller, counter wraps
+// aren't detected, it's up to the caller not to generate more than 1<<24
+// tokens with the same time.
+func (g *Generator) NewWithTime(t time.Time) Token {
+	generated(1)
+	return g.newToken(uint32(t.Unix()-g.epoch), atomic.AddUint32(g.counter, g.step()))
+}
+
+// NewWithTimeE is like NewWithTime but returns a *TimeOutOfRangeError, which
+// satisfies errors.Is(err, ErrTimeOutOfRange), when t is before the epoch of
+// the Generator or more than math.MaxUint32 seconds after it.
+func (g *Generator) NewWithTimeE(t time.Time) (Token, error) {
+	secs, err := g.checkedSeconds(t)
+	if err != nil {
+		return nilToken, err
+	}
+	generated(1)
+	return g.newToken(secs, atomic.AddUint32(g.counter, g.step())), nil
+}
+
+// Time returns the timestamp part of the token generated by g, taking the epoch
+// of the Generator into account.
+func (g *Generator) Time(token Token) time.Time {
+	secs := int64(binary.BigEndian.Uint32(token[0:4]))
+	return time.Unix(g.epoch+secs, 0)
+}
+
+// checkedSeconds returns the timestamp stored for t, it fails when t is out of range.
+func (g *Generator) checkedSeconds(t time.Time) (uint32, error) {
+	return checkedSeconds(t, g.epoch)
+}
@@ -0,0 +1,637 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGeneratorEpoch(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := NewGenerator(WithEpoch(epoch))
+
+	now := time.Unix(time.Now().Unix(), 0)
+	token := g.NewWithTime(now)
+	if got := g.Time(token); !got.Equal(now) {
+		t.Errorf("g.Time() = %v, want %v", got, now)
+	}
+	if got, want := token.Time(), time.Unix(now.Unix()-epoch.Unix(), 0); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v", got, want)
+	}
+
+	// The default generator keeps the Unix epoch.
+	if got := defaultGenerator.Time(NewWithTime(now)); !got.Equal(now) {
+		t.Errorf("defaultGenerator.Time() = %v, want %v", got, now)
+	}
+}
+
+func TestGeneratorEpochBoundaries(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := NewGenerator(WithEpoch(epoch))
+	last := epoch.Add(math.MaxUint32 * time.Second)
+
+	tests := []struct {
+		name string
+		time time.Time
+		err  error
+	}{
+		{"epoch", epoch, nil},
+		{"unix horizon", time.Unix(math.MaxUint32, 0), nil},
+		{"past unix horizon", time.Unix(math.MaxUint32+1, 0), nil},
+		{"last second", last, nil},
+		{"past last second", last.Add(time.Second), ErrTimeOutOfRange},
+		{"before epoch", epoch.Add(-time.Second), ErrTimeOutOfRange},
+		{"unix epoch", time.Unix(0, 0), ErrTimeOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := g.NewWithTimeE(tt.time)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NewWithTimeE() err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				if !token.IsZero() {
+					t.Errorf("NewWithTimeE() = %v, want nil token", token)
+				}
+				// NewWithTime wraps instead, so the time can't be read back.
+				if got := g.Time(g.NewWithTime(tt.time)); got.Equal(tt.time) {
+					t.Errorf("g.Time() = %v, want a wrapped time", got)
+				}
+				return
+			}
+			if got := g.Time(token); !got.Equal(tt.time) {
+				t.Errorf("g.Time() = %v, want %v", got, tt.time)
+			}
+		})
+	}
+}
+
+// setCounter sets the counter of g so that its next token of secs gets value
+// next, with the window of secs opened at start.
+func setCounter(g *Generator, secs, start, next uint32) {
+	atomic.StoreUint32(&g.generation, atomic.LoadUint32(&counterGeneration))
+	atomic.StoreUint64(&g.window, uint64(secs)<<32|uint64(start))
+	atomic.StoreUint32(g.counter, next-1)
+}
+
+func TestCounterOverflow(t *testing.T) {
+	secs := uint32(time.Now().Unix())
+	start := uint32(0xABCDEF)
+	setCounter(defaultGenerator, secs, start, start)
+	first, err := defaultGenerator.newStrict(secs)
+	if err != nil {
+		t.Fatalf("newStrict() err: %v", err)
+	}
+
+	setCounter(defaultGenerator, secs, start, start+1<<24-3)
+	for i := 0; i < 3; i++ {
+		token, err := defaultGenerator.newStrict(secs)
+		if err != nil {
+			t.Fatalf("newStrict() #%d err: %v", i, err)
+		}
+		if token == first {
+			t.Fatalf("newStrict() #%d repeated the first token of the second", i)
+		}
+	}
+	// The next value has the same 3 bytes as the first token.
+	if _, err := defaultGenerator.newStrict(secs); err != ErrCounterOverflow {
+		t.Fatalf("newStrict() err = %v, want %v", err, ErrCounterOverflow)
+	}
+	// A new second opens a new window.
+	if _, err := defaultGenerator.newStrict(secs + 1); err != nil {
+		t.Fatalf("newStrict() next second err: %v", err)
+	}
+}
+
+func TestNewWaitsOnCounterOverflow(t *testing.T) {
+	now := time.Now()
+	secs := uint32(now.Unix())
+	start := uint32(0x123456)
+	setCounter(defaultGenerator, secs, start, start+1<<24)
+	token := New()
+	if got := token.Time().Unix(); got <= now.Unix() {
+		t.Errorf("New() secs = %d, want > %d", got, now.Unix())
+	}
+}
+
+func TestCounterWindowPerGenerator(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now))
+	secs := uint32(clock.Now().Unix())
+	start := uint32(0x123456)
+	setCounter(g, secs, start, start+1<<24-1)
+	// Generators sharing the counter with another notion of the current second
+	// don't reset the window of g.
+	other := NewGenerator(WithClock(clock.Now), WithEpoch(time.Unix(1600000000, 0)))
+	if _, err := other.NewStrict(); err != nil {
+		t.Fatalf("other.NewStrict() err: %v", err)
+	}
+	if _, err := g.NewStrict(); err != ErrCounterOverflow {
+		t.Fatalf("NewStrict() err = %v, want %v", err, ErrCounterOverflow)
+	}
+}
+
+func TestNewClockStalled(t *testing.T) {
+	// 1ms before the next second, New doesn't wait long.
+	clock := &fakeClock{now: time.Unix(1700000000, 0).Add(-time.Millisecond)}
+	g := NewGenerator(WithClock(clock.Now))
+	secs := uint32(clock.Now().Unix())
+	start := uint32(0x123456)
+	setCounter(g, secs, start, start+1<<24)
+	if _, err := g.NewE(); err != ErrClockStalled {
+		t.Fatalf("NewE() with a frozen clock err = %v, want %v", err, ErrClockStalled)
+	}
+
+	// New blocks until the clock reaches the next second.
+	done := make(chan Token)
+	go func() { done <- g.New() }()
+	select {
+	case token := <-done:
+		t.Fatalf("New() with a frozen clock = %x, want it to block", token[:])
+	case <-time.After(50 * time.Millisecond):
+	}
+	clock.Add(time.Millisecond)
+	if token := <-done; uint32(token.Unix()) != secs+1 {
+		t.Errorf("New() secs = %d, want %d", token.Unix(), secs+1)
+	}
+}
+
+func TestGeneratorOverflowRandom(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithOverflowPolicy(OverflowRandom))
+	secs := uint32(clock.Now().Unix())
+	start := uint32(0x123456)
+	setCounter(g, secs, start, start+1<<24)
+	before := Stats().CounterOverflows
+	token := g.New()
+	if !token.IsRandom() {
+		t.Errorf("New() on overflow = %x, want a random token", token[:])
+	}
+	if got := uint32(token.Time().Unix()); got != secs {
+		t.Errorf("New() on overflow secs = %d, want %d", got, secs)
+	}
+	if got := Stats().CounterOverflows - before; got != 1 {
+		t.Errorf("CounterOverflows grew by %d, want 1", got)
+	}
+
+	// Without overflow, the counter is used as usual.
+	setCounter(g, secs, start, start+1)
+	token = g.New()
+	if want := int32((start + 1) & maxCounter); token.IsRandom() || token.Counter() != want {
+		t.Errorf("New() = %x, want counter %x", token[:], want)
+	}
+}
+
+func TestGeneratorOverflowRandomMonotonic(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMonotonic(), WithOverflowPolicy(OverflowRandom))
+	first := g.New()
+	secs := uint32(first.Unix())
+	start := atomic.LoadUint32(&objectIDCounter) & maxCounter
+	setCounter(g, secs, start, start+1<<24)
+	// A random token would break the order, New moves on to the next second.
+	token := g.New()
+	if token.IsRandom() || token.Compare(first) <= 0 || uint32(token.Unix()) != secs+1 {
+		t.Errorf("New() on overflow = %x, want a token of the next second after %x", token[:], first[:])
+	}
+}
+
+func TestGeneratorOverflowError(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	for _, opts := range [][]Option{nil, {WithMonotonic()}} {
+		g := NewGenerator(append(opts, WithClock(clock.Now), WithOverflowPolicy(OverflowError))...)
+		secs := uint32(clock.Now().Unix())
+		start := uint32(0x123456)
+		setCounter(g, secs, start, start+1<<24)
+		before := Stats().CounterOverflows
+		if _, err := g.NewE(); err != ErrCounterOverflow {
+			t.Errorf("NewE() on overflow err = %v, want %v", err, ErrCounterOverflow)
+		}
+		if got := Stats().CounterOverflows - before; got != 1 {
+			t.Errorf("CounterOverflows grew by %d, want 1", got)
+		}
+		setCounter(g, secs, start, start+1)
+		if token, err := g.NewE(); err != nil || token.Counter() != int32((start+1)&maxCounter) {
+			t.Errorf("NewE() = %x, %v, want counter %x", token[:], err, start+1)
+		}
+	}
+
+	// New waits for the next second instead, 1ms away.
+	clock = &fakeClock{now: time.Unix(1700000000, 0).Add(-time.Millisecond)}
+	g := NewGenerator(WithClock(clock.Now), WithOverflowPolicy(OverflowError))
+	secs := uint32(clock.Now().Unix())
+	start := uint32(0x123456)
+	setCounter(g, secs, start, start+1<<24)
+	done := make(chan Token)
+	go func() { done <- g.New() }()
+	time.Sleep(10 * time.Millisecond)
+	clock.Add(time.Millisecond)
+	if token := <-done; uint32(token.Unix()) != secs+1 {
+		t.Errorf("New() on overflow secs = %d, want %d", token.Unix(), secs+1)
+	}
+}
+
+func TestCounterWindowConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				if _, err := NewStrict(); err != nil {
+					t.Errorf("NewStrict() err: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkNewWithTime is the baseline without counter wrap detection, to
+// compare with BenchmarkNew.
+func BenchmarkNewWithTime(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = NewWithTime(time.Now())
+		}
+	})
+}
+
+// fakeClock is a clock tests can move freely.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestGeneratorMonotonic(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMonotonic())
+	// Keep the 3-byte counter away from its byte-wise wrap.
+	atomic.StoreUint32(&objectIDCounter, 0)
+
+	prev := g.New()
+	for _, step := range []time.Duration{time.Second, -10 * time.Second, 0, 3 * time.Second, -time.Hour, 2 * time.Hour} {
+		clock.Add(step)
+		for i := 0; i < 3; i++ {
+			token := g.New()
+			if token.Compare(prev) <= 0 {
+				t.Fatalf("New() after clock step %v = %x, want > %x", step, token[:], prev[:])
+			}
+			prev = token
+		}
+	}
+	if got, want := prev.Time(), clock.Now(); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v once the clock caught up", got, want)
+	}
+
+	// Without the option, tokens follow the clock backwards.
+	g = NewGenerator(WithClock(clock.Now))
+	before := g.New()
+	clock.Add(-time.Minute)
+	if after := g.New(); after.Compare(before) >= 0 {
+		t.Errorf("New() without WithMonotonic didn't follow the clock backwards")
+	}
+}
+
+func TestGeneratorMonotonicOverflow(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMonotonic())
+	first := g.New()
+	secs := uint32(first.Time().Unix())
+	start := atomic.LoadUint32(&objectIDCounter)
+	setCounter(g, secs, start, start+1<<24)
+
+	// The clock is frozen, New moves on to the next second instead of waiting.
+	token := g.New()
+	if got, want := token.Time().Unix(), int64(secs)+1; got != want {
+		t.Errorf("New() secs = %d, want %d", got, want)
+	}
+	if token.Compare(first) <= 0 {
+		t.Errorf("New() = %x, want > %x", token[:], first[:])
+	}
+}
+
+func TestGeneratorMonotonicCounterRollover(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMonotonic(), WithCounter(1<<24-3))
+	prev := g.New()
+	for i := 0; i < 5; i++ {
+		token := g.New()
+		if token.Compare(prev) <= 0 {
+			t.Fatalf("New() after counter %d = %x, want > %x", prev.Counter(), token[:], prev[:])
+		}
+		prev = token
+	}
+	if got, want := prev.Time(), clock.Now().Add(time.Second); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v after the counter rolled over", got, want)
+	}
+}
+
+func TestGeneratorMonotonicCounterRolloverStrict(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMonotonic(), WithCounter(1<<24-3))
+	var tokens []Token
+	overflows := 0
+	for len(tokens) < 5 {
+		token, err := g.NewStrict()
+		if err == ErrCounterOverflow {
+			overflows++
+			continue
+		}
+		if err != nil {
+			t.Fatalf("NewStrict() err: %v", err)
+		}
+		if n := len(tokens); n > 0 && token.Compare(tokens[n-1]) <= 0 {
+			t.Fatalf("NewStrict() after counter %d = %x, want > %x", tokens[n-1].Counter(), token[:], tokens[n-1][:])
+		}
+		tokens = append(tokens, token)
+	}
+	if overflows != 1 {
+		t.Errorf("NewStrict() returned %d overflows, want 1", overflows)
+	}
+	if got, want := tokens[4].Time(), clock.Now().Add(time.Second); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v after the counter rolled over", got, want)
+	}
+}
+
+func TestGeneratorRandomCounterStep(t *testing.T) {
+	const maxStep = 16
+	g := NewGenerator(WithRandomCounterStep(maxStep))
+	n := 1000000
+	if testing.Short() {
+		n = 100000
+	}
+	seen := make(map[Token]struct{}, n)
+	steps := make(map[int32]int)
+	prev := g.New()
+	for i := 0; i < n; i++ {
+		token := g.New()
+		if _, ok := seen[token]; ok {
+			t.Fatalf("New() generated a duplicate after %d tokens", i)
+		}
+		seen[token] = struct{}{}
+		if token.Time().Equal(prev.Time()) {
+			// Counter is 3 bytes, compute the delta modulo 1<<24.
+			delta := (token.Counter() - prev.Counter()) & 0xFFFFFF
+			if delta < 1 || delta > maxStep {
+				t.Fatalf("counter delta = %d, want within [1, %d]", delta, maxStep)
+			}
+			steps[delta]++
+		}
+		prev = token
+	}
+	if len(steps) < maxStep/2 {
+		t.Errorf("only %d distinct counter deltas, want random steps", len(steps))
+	}
+}
+
+// BenchmarkGeneratorRandomCounterStep includes the waits for the next second
+// once the counter space of the current one is used up.
+func BenchmarkGeneratorRandomCounterStep(b *testing.B) {
+	g := NewGenerator(WithRandomCounterStep(16))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = g.New()
+		}
+	})
+}
+
+func TestGeneratorShardedCounter(t *testing.T) {
+	for _, g := range []*Generator{
+		NewGenerator(WithShardedCounter()),
+		NewGenerator(WithShardedCounter(), WithRandomCounterStep(4)),
+		NewGenerator(WithShardedCounter(), WithMonotonic()),
+	} {
+		const goroutines, n = 64, 5000
+		results := make([][]Token, goroutines)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				tokens := make([]Token, n)
+				for j := range tokens {
+					tokens[j] = g.New()
+				}
+				results[i] = tokens
+			}(i)
+		}
+		wg.Wait()
+		seen := make(map[Token]struct{}, goroutines*n)
+		for _, tokens := range results {
+			for _, token := range tokens {
+				if _, ok := seen[token]; ok {
+					t.Fatalf("New() generated a duplicate")
+				}
+				seen[token] = struct{}{}
+			}
+		}
+	}
+}
+
+// BenchmarkGeneratorShardedCounter compares the shared and the sharded
+// counters under parallel load, run it with e.g. -cpu 1,4,16,64.
+func BenchmarkGeneratorShardedCounter(b *testing.B) {
+	for _, bb := range []struct {
+		name string
+		g    *Generator
+	}{
+		{"counter=shared", NewGenerator()},
+		{"counter=sharded", NewGenerator(WithShardedCounter())},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = bb.g.New()
+				}
+			})
+		})
+	}
+}
+
+func TestGeneratorOwnState(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	newGen := func() *Generator {
+		return NewGenerator(
+			WithClock(func() time.Time { return now }),
+			WithCounter(41),
+			WithPid(7),
+			WithRandReader(bytes.NewReader(make([]byte, 8))),
+			WithRandomCounterStep(4),
+		)
+	}
+	shared := atomic.LoadUint32(&objectIDCounter)
+	a, b := newGen(), newGen()
+	for i := 0; i < 10; i++ {
+		ta, tb := a.New(), b.New()
+		if ta != tb {
+			t.Fatalf("token %d = %v and %v, want the same token from identical Generators", i, ta, tb)
+		}
+		if ta.Pid() != 7 || !ta.Time().Equal(now) {
+			t.Errorf("token %d has pid %d and time %v, want 7 and %v", i, ta.Pid(), ta.Time(), now)
+		}
+	}
+	if got := atomic.LoadUint32(&objectIDCounter); got != shared {
+		t.Errorf("package-level counter = %d, want %d", got, shared)
+	}
+
+	g := NewGenerator(WithCounter(41))
+	if got := g.NewWithTime(now).Counter(); got != 42 {
+		t.Errorf("NewWithTime().Counter() = %d, want 42", got)
+	}
+	if got := g.New().Counter(); got != 43 {
+		t.Errorf("New().Counter() = %d, want 43", got)
+	}
+}
+
+// TestGeneratorGolden checks that a Generator with its own clock, counter, pid
+// and machine id gives the same tokens across runs and hosts, so they can be
+// compared with golden files through CanonicalString.
+func TestGeneratorGolden(t *testing.T) {
+	g := NewGenerator(
+		WithClock(func() time.Time { return time.Unix(1700000000, 0) }),
+		WithCounter(0),
+		WithPid(1),
+		WithMachineID([]byte("golden")),
+	)
+	want := []string{
+		"gLaKrUE_adJOmBNFafKaaaCeaPIaaiDa",
+		"gLaKrUE_adJOmBNFafKaaaCeaPIaaqDA",
+		"gLaKrUE_adJOmBNFafKaaaCeaPIaayDA",
+	}
+	for i, w := range want {
+		if got := g.New().CanonicalString(); got != w {
+			t.Errorf("token %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestGeneratorRandReaderError(t *testing.T) {
+	if _, err := NewGeneratorE(WithRandomCounterStep(4), WithRandReader(failingReader{})); !errors.Is(err, errReader) {
+		t.Errorf("NewGeneratorE() err = %v, want %v", err, errReader)
+	}
+}
+
+func TestGeneratorMaxPerSecond(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMaxPerSecond(3))
+	for i := 0; i < 3; i++ {
+		if _, err := g.NewStrict(); err != nil {
+			t.Fatalf("NewStrict() #%d err: %v", i, err)
+		}
+	}
+	if _, err := g.NewStrict(); err != ErrRateLimited {
+		t.Errorf("NewStrict() over the limit err = %v, want %v", err, ErrRateLimited)
+	}
+	clock.Add(time.Second)
+	if _, err := g.NewStrict(); err != nil {
+		t.Errorf("NewStrict() in the next second err: %v", err)
+	}
+}
+
+func TestGeneratorMaxPerSecondClock(t *testing.T) {
+	// 5ms before a second, and every read of the clock advances it by 1ms.
+	clock := &fakeClock{now: time.Unix(1700000000, 0).Add(-5 * time.Millisecond)}
+	g := NewGenerator(WithMaxPerSecond(2), WithClock(func() time.Time {
+		clock.Add(time.Millisecond)
+		return clock.Now()
+	}))
+	tokens := append(g.NewBatch(3), g.New())
+	if got, want := []int64{tokens[0].Unix(), tokens[1].Unix(), tokens[2].Unix(), tokens[3].Unix()},
+		[]int64{1699999999, 1699999999, 1700000000, 1700000000}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens secs = %v, want %v", got, want)
+	}
+
+	// A frozen clock never reaches the next second.
+	frozen := &fakeClock{now: time.Unix(1700000000, 0).Add(-time.Millisecond)}
+	g = NewGenerator(WithMaxPerSecond(1), WithClock(frozen.Now))
+	g.New()
+	if _, err := g.NewE(); err != ErrClockStalled {
+		t.Errorf("NewE() over the limit with a frozen clock err = %v, want %v", err, ErrClockStalled)
+	}
+	done := make(chan []Token)
+	go func() { done <- g.NewBatch(1) }()
+	select {
+	case <-done:
+		t.Fatal("NewBatch() over the limit with a frozen clock didn't block")
+	case <-time.After(50 * time.Millisecond):
+	}
+	frozen.Add(time.Millisecond)
+	if tokens := <-done; tokens[0].Unix() != 1700000000 {
+		t.Errorf("NewBatch() secs = %d, want %d", tokens[0].Unix(), 1700000000)
+	}
+}
+
+func TestGeneratorMaxPerSecondStamp(t *testing.T) {
+	// Every read of the clock advances it by 1ms, the first one is the last
+	// millisecond of a second.
+	clock := &fakeClock{now: time.Unix(1700000000, 0).Add(-2 * time.Millisecond)}
+	g := NewGenerator(WithMaxPerSecond(1), WithClock(func() time.Time {
+		clock.Add(time.Millisecond)
+		return clock.Now()
+	}))
+	// Each token is counted in the second it's stamped with, so both fit.
+	if a, b := g.New(), g.New(); a.Unix() != 1699999999 || b.Unix() != 1700000000 {
+		t.Errorf("tokens secs = %d, %d, want %d, %d", a.Unix(), b.Unix(), 1699999999, 1700000000)
+	}
+}
+
+func TestGeneratorMaxPerSecondBackwards(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	g := NewGenerator(WithClock(clock.Now), WithMaxPerSecond(2))
+	for i := 0; i < 2; i++ {
+		if _, err := g.NewStrict(); err != nil {
+			t.Fatalf("NewStrict() #%d err: %v", i, err)
+		}
+	}
+	// Stepping the clock back and forth doesn't reset the count.
+	clock.Add(-time.Second)
+	if _, err := g.NewStrict(); err != ErrRateLimited {
+		t.Errorf("NewStrict() after the clock stepped back err = %v, want %v", err, ErrRateLimited)
+	}
+	clock.Add(time.Second)
+	if _, err := g.NewStrict(); err != ErrRateLimited {
+		t.Errorf("NewStrict() after the clock stepped forward err = %v, want %v", err, ErrRateLimited)
+	}
+}
+
+func TestGeneratorMaxPerSecondWaits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the next seconds")
+	}
+	g := NewGenerator(WithMaxPerSecond(2))
+	tokens := append(g.NewBatch(3), g.New(), g.NewTagged(1))
+	perSecond := make(map[int64]int)
+	for _, token := range tokens {
+		perSecond[token.Unix()]++
+	}
+	if len(perSecond) < 3 {
+		t.Errorf("5 tokens issued in %d seconds, want at least 3", len(perSecond))
+	}
+	for secs, n := range perSecond {
+		if n > 2 {
+			t.Errorf("%d tokens issued at %d, want at most 2", n, secs)
+		}
+	}
+}
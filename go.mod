@@ -1,3 +1,3 @@
 module github.com/zdz1715/xtoken
 
-go 1.16
+go 1.22
@@ -0,0 +1,71 @@
+// Package gormcompat registers GORM serializers for xtoken.Token fields.
+//
+// Importing the package registers:
+//   - "xtoken", storing the string representation, e.g. in a CHAR(32) column,
+//   - "xtoken_hex", storing the hex representation, e.g. in a CHAR(24) column,
+//   - "xtoken_binary", storing the 12 raw bytes, e.g. in a BINARY(12) column.
+//
+// Use them with the serializer tag:
+//
+//	type Session struct {
+//		ID xtoken.Token `gorm:"serializer:xtoken_binary"`
+//	}
+//
+// All of them read the three formats, and the nil token is stored as NULL.
+//
+// The Token and BinaryToken field types need no tag, AutoMigrate creates
+// their columns with a type suited to the dialect.
+package gormcompat
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/zdz1715/xtoken"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("xtoken", Serializer{Format: xtoken.FormatString})
+	schema.RegisterSerializer("xtoken_hex", Serializer{Format: xtoken.FormatHex})
+	schema.RegisterSerializer("xtoken_binary", Serializer{Format: xtoken.FormatBinary})
+}
+
+// Serializer is a GORM serializer for xtoken.Token and *xtoken.Token fields,
+// writing them in Format.
+type Serializer struct {
+	Format xtoken.StorageFormat
+}
+
+// Scan implements schema.SerializerInterface.
+func (s Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var token xtoken.Token
+	if err := token.Scan(dbValue); err != nil {
+		return err
+	}
+	switch field.FieldType {
+	case reflect.TypeOf(token):
+		return field.Set(ctx, dst, token)
+	case reflect.TypeOf(&token):
+		if dbValue == nil {
+			return field.Set(ctx, dst, (*xtoken.Token)(nil))
+		}
+		return field.Set(ctx, dst, &token)
+	}
+	return fmt.Errorf("gormcompat: unsupported field type %v", field.FieldType)
+}
+
+// Value implements schema.SerializerValuerInterface.
+func (s Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case xtoken.Token:
+		return v.ValueAs(s.Format)
+	case *xtoken.Token:
+		if v == nil {
+			return nil, nil
+		}
+		return v.ValueAs(s.Format)
+	}
+	return nil, fmt.Errorf("gormcompat: unsupported field type %T", fieldValue)
+}
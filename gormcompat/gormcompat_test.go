@@ -0,0 +1,86 @@
+package gormcompat
+
+import (
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type record struct {
+	ID       uint
+	Str      xtoken.Token  `gorm:"serializer:xtoken;type:char(32)"`
+	Hex      xtoken.Token  `gorm:"serializer:xtoken_hex;type:char(24)"`
+	Bin      xtoken.Token  `gorm:"serializer:xtoken_binary;type:binary(12)"`
+	Optional *xtoken.Token `gorm:"serializer:xtoken_binary;type:binary(12)"`
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() err: %v", err)
+	}
+	if err := db.AutoMigrate(&record{}); err != nil {
+		t.Fatalf("AutoMigrate() err: %v", err)
+	}
+	return db
+}
+
+func TestSerializer(t *testing.T) {
+	db := openDB(t)
+	opt := xtoken.New()
+	want := record{Str: xtoken.New(), Hex: xtoken.New(), Bin: xtoken.New(), Optional: &opt}
+	if err := db.Create(&want).Error; err != nil {
+		t.Fatalf("Create() err: %v", err)
+	}
+	var got record
+	if err := db.First(&got, want.ID).Error; err != nil {
+		t.Fatalf("First() err: %v", err)
+	}
+	if got.Str != want.Str || got.Hex != want.Hex || got.Bin != want.Bin || got.Optional == nil || *got.Optional != opt {
+		t.Errorf("First() = %+v, want %+v", got, want)
+	}
+
+	// The columns hold the configured formats.
+	var raw struct {
+		Str, Hex string
+		Bin      []byte
+	}
+	if err := db.Table("records").Select("str, hex, bin").Where("id = ?", want.ID).Scan(&raw).Error; err != nil {
+		t.Fatalf("Scan() err: %v", err)
+	}
+	if raw.Hex != want.Hex.Hex() || string(raw.Bin) != string(want.Bin.Bytes()) {
+		t.Errorf("columns = %+v, want the hex and raw bytes", raw)
+	}
+	if token, err := xtoken.FromString(raw.Str); err != nil || token != want.Str {
+		t.Errorf("str column = %q, want the string representation of %v", raw.Str, want.Str)
+	}
+
+	// Lookups by token use the column format.
+	var found record
+	if err := db.Where("bin = ?", want.Bin.Bytes()).First(&found).Error; err != nil || found.ID != want.ID {
+		t.Errorf("Where(bin) = %+v, %v, want record %d", found, err, want.ID)
+	}
+}
+
+func TestSerializerNil(t *testing.T) {
+	db := openDB(t)
+	want := record{}
+	if err := db.Create(&want).Error; err != nil {
+		t.Fatalf("Create() err: %v", err)
+	}
+	var count int64
+	if err := db.Table("records").Where("bin IS NULL AND optional IS NULL").Count(&count).Error; err != nil || count != 1 {
+		t.Errorf("Count() = %d, %v, want 1 row of NULLs", count, err)
+	}
+	var got record
+	if err := db.First(&got, want.ID).Error; err != nil {
+		t.Fatalf("First() err: %v", err)
+	}
+	if !got.Str.IsZero() || !got.Bin.IsZero() || got.Optional != nil {
+		t.Errorf("First() = %+v, want nil tokens", got)
+	}
+}
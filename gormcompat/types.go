@@ -0,0 +1,77 @@
+package gormcompat
+
+import (
+	"database/sql/driver"
+
+	"github.com/zdz1715/xtoken"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Token is an xtoken.Token field stored as its CanonicalString, so that the
+// same token is always the same 32 chars for lookups and unique indexes. It
+// needs no tag: AutoMigrate creates a CHAR(32) column with a case-sensitive
+// collation where the default one isn't, e.g. on MySQL. Convert it with
+// xtoken.Token(t), the nil token is stored as NULL.
+type Token xtoken.Token
+
+// Value implements driver.Valuer.
+func (t Token) Value() (driver.Value, error) {
+	if xtoken.Token(t).IsZero() {
+		return nil, nil
+	}
+	return xtoken.Token(t).CanonicalString(), nil
+}
+
+// Scan implements sql.Scanner, it accepts the same values as xtoken.Token.Scan.
+func (t *Token) Scan(src interface{}) error {
+	return (*xtoken.Token)(t).Scan(src)
+}
+
+// GormDataType implements schema.GormDataTypeInterface.
+func (Token) GormDataType() string {
+	return string(schema.String)
+}
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (Token) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return "CHAR(32) CHARACTER SET ascii COLLATE ascii_bin"
+	case "sqlserver":
+		return "CHAR(32) COLLATE Latin1_General_BIN"
+	}
+	return "CHAR(32)"
+}
+
+// BinaryToken is an xtoken.Token field stored as its 12 raw bytes. It needs no
+// tag: AutoMigrate creates a BYTEA column on PostgreSQL, BLOB on SQLite and
+// BINARY(12) otherwise. Convert it with xtoken.Token(t), the nil token is
+// stored as NULL.
+type BinaryToken xtoken.Token
+
+// Value implements driver.Valuer.
+func (t BinaryToken) Value() (driver.Value, error) {
+	return xtoken.Token(t).ValueAs(xtoken.FormatBinary)
+}
+
+// Scan implements sql.Scanner, it accepts the same values as xtoken.Token.Scan.
+func (t *BinaryToken) Scan(src interface{}) error {
+	return (*xtoken.Token)(t).Scan(src)
+}
+
+// GormDataType implements schema.GormDataTypeInterface.
+func (BinaryToken) GormDataType() string {
+	return string(schema.Bytes)
+}
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (BinaryToken) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "BYTEA"
+	case "sqlite":
+		return "BLOB"
+	}
+	return "BINARY(12)"
+}
@@ -0,0 +1,62 @@
+package gormcompat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type session struct {
+	ID      BinaryToken `gorm:"primaryKey"`
+	Ref     Token       `gorm:"uniqueIndex"`
+	Parent  *BinaryToken
+	Comment string
+}
+
+func TestTypes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() err: %v", err)
+	}
+	if err := db.AutoMigrate(&session{}); err != nil {
+		t.Fatalf("AutoMigrate() err: %v", err)
+	}
+	columns, err := db.Migrator().ColumnTypes(&session{})
+	if err != nil {
+		t.Fatalf("ColumnTypes() err: %v", err)
+	}
+	types := make(map[string]string)
+	for _, c := range columns {
+		types[c.Name()] = strings.ToUpper(c.DatabaseTypeName())
+	}
+	if types["id"] != "BLOB" || types["ref"] != "CHAR" || types["parent"] != "BLOB" {
+		t.Errorf("column types = %v, want BLOB and CHAR", types)
+	}
+
+	id, ref := xtoken.New(), xtoken.New()
+	if err := db.Create(&session{ID: BinaryToken(id), Ref: Token(ref)}).Error; err != nil {
+		t.Fatalf("Create() err: %v", err)
+	}
+	// The canonical string is the same for every lookup.
+	var got session
+	if err := db.Where("ref = ?", Token(ref)).First(&got).Error; err != nil {
+		t.Fatalf("First() err: %v", err)
+	}
+	if xtoken.Token(got.ID) != id || xtoken.Token(got.Ref) != ref || got.Parent != nil {
+		t.Errorf("First() = %+v, want id %v and ref %v", got, id, ref)
+	}
+	var raw struct {
+		ID  []byte
+		Ref string
+	}
+	if err := db.Table("sessions").Select("id, ref").Scan(&raw).Error; err != nil {
+		t.Fatalf("Scan() err: %v", err)
+	}
+	if string(raw.ID) != string(id.Bytes()) || raw.Ref != ref.CanonicalString() {
+		t.Errorf("columns = %+v, want the raw bytes and the canonical string", raw)
+	}
+}
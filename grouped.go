@@ -0,0 +1,131 @@
+package xtoken
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LooseSeparators are the separators ParseLoose strips.
+const LooseSeparators = " .-"
+
+// FormatGrouped returns the string representation of the token split into
+// groups of groupSize chars joined by sep, e.g. "aB3d-Ef9k-…" with groups of
+// 4, to make it easier to read out. The last group is shorter when groupSize
+// doesn't divide 32. It returns the string representation unchanged when
+// groupSize is not in [1, 31]. ParseLoose reads it back for the separators of
+// LooseSeparators and group sizes up to 16.
+func (token Token) FormatGrouped(sep rune, groupSize int) string {
+	s := token.String()
+	if groupSize < 1 || groupSize >= encodedLen {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(encodedLen + (encodedLen-1)/groupSize*4)
+	for i := 0; i < encodedLen; i += groupSize {
+		if i > 0 {
+			b.WriteRune(sep)
+		}
+		b.WriteString(s[i:min(i+groupSize, encodedLen)])
+	}
+	return b.String()
+}
+
+// ParseLoose is like FromString but tolerates surrounding whitespace and the
+// separators of LooseSeparators, e.g. in the output of FormatGrouped. Text
+// pasted from emails or documents is normalized first: fullwidth forms are
+// folded to ASCII, Unicode dashes to '-', Unicode spaces to ' ' and invisible
+// chars such as zero-width spaces or soft hyphens are dropped. The alphabet of
+// String is case-sensitive and holds look-alikes such as 0 and O, so they are
+// kept as is, but 24 chars are read as hex, in either case.
+func ParseLoose(s string) (Token, error) {
+	return ParseLooseSeparators(s, LooseSeparators)
+}
+
+// ParseLooseSeparators is like ParseLoose with the separators of seps.
+//
+// Separators outside of the alphabet of String are removed wherever they are.
+// The ones of the alphabet, such as '-', may also be chars of the token, so
+// they are only removed when they are found between every group of a
+// FormatGrouped layout with a group size up to 16, that is at every
+// groupSize+1 chars with as many separators as needed to get 32 chars.
+func ParseLooseSeparators(s, seps string) (Token, error) {
+	s = strings.TrimSpace(strings.Map(normalizeLoose, s))
+	if len(s) == encodedLen {
+		return FromString(s)
+	}
+	var inAlphabet []byte
+	for i := 0; i < len(seps); i++ {
+		if StdEncoding.dec[seps[i]] != 0xFF {
+			inAlphabet = append(inAlphabet, seps[i])
+		}
+	}
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(seps, r) && (r >= 0x80 || StdEncoding.dec[r] == 0xFF) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) != encodedLen {
+		for _, sep := range inAlphabet {
+			if ungrouped, ok := ungroup(s, sep); ok {
+				s = ungrouped
+				break
+			}
+		}
+	}
+	if len(s) == hexEncodedLen {
+		return FromHex(s)
+	}
+	return FromString(s)
+}
+
+// normalizeLoose maps the look-alikes of ASCII chars ParseLoose tolerates to
+// them, and drops the invisible ones.
+func normalizeLoose(r rune) rune {
+	switch {
+	case r < 0x80:
+		return r
+	case r >= 0xFF01 && r <= 0xFF5E: // fullwidth ASCII
+		return r - 0xFEE0
+	case r >= 0x2010 && r <= 0x2015, r == 0x2212, r == 0xFE63: // dashes and minus signs
+		return '-'
+	case r == 0xAD, r >= 0x200B && r <= 0x200D, r == 0x2060, r == 0xFEFF: // invisible
+		return -1
+	case unicode.IsSpace(r):
+		return ' '
+	}
+	return r
+}
+
+// maxGroupSize is the largest group size ParseLoose recognizes for separators
+// of the alphabet. Larger groups only need one separator, which could be
+// any '-' of their second half.
+const maxGroupSize = encodedLen / 2
+
+// ungroup removes sep from s when it's found between every group of a
+// FormatGrouped layout of 32 chars, trying the group sizes from the largest.
+func ungroup(s string, sep byte) (string, bool) {
+	for size := maxGroupSize; size > 0; size-- {
+		if len(s) != encodedLen+(encodedLen-1)/size {
+			continue
+		}
+		grouped := true
+		for i := size; i < len(s); i += size + 1 {
+			if s[i] != sep {
+				grouped = false
+				break
+			}
+		}
+		if !grouped {
+			continue
+		}
+		b := make([]byte, 0, encodedLen)
+		for i := 0; i < len(s); i += size + 1 {
+			b = append(b, s[i:min(i+size, len(s))]...)
+		}
+		if ValidateBytes(b) == nil {
+			return string(b), true
+		}
+	}
+	return "", false
+}
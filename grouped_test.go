@@ -0,0 +1,111 @@
+package xtoken
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatGrouped(t *testing.T) {
+	token := New()
+	s := token.FormatGrouped('-', 4)
+	if len(s) != encodedLen+7 || strings.Count(s, "-") < 7 {
+		t.Fatalf("FormatGrouped('-', 4) = %q, want 8 groups of 4", s)
+	}
+	for i := 4; i < len(s); i += 5 {
+		if s[i] != '-' {
+			t.Fatalf("FormatGrouped('-', 4) = %q, want '-' at %d", s, i)
+		}
+	}
+	for _, size := range []int{0, -1, encodedLen, 100} {
+		if got := token.FormatGrouped('-', size); len(got) != encodedLen {
+			t.Errorf("FormatGrouped('-', %d) = %q, want the ungrouped form", size, got)
+		}
+	}
+	if got := token.FormatGrouped('·', 16); len(got) != encodedLen+len("·") {
+		t.Errorf("FormatGrouped('·', 16) = %q, want 2 groups", got)
+	}
+}
+
+func TestParseLooseRoundTrip(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		token := New()
+		for size := 2; size <= 8; size++ {
+			for _, sep := range LooseSeparators {
+				s := token.FormatGrouped(sep, size)
+				got, err := ParseLoose(s)
+				if err != nil || got != token {
+					t.Fatalf("ParseLoose(%q) = %v, %v, want %v", s, got, err, token)
+				}
+			}
+		}
+	}
+}
+
+// randomString returns a random token and its String representation, which
+// contains a dash or not. Tokens of New within a second share their time,
+// machine id and pid chars, which may always or never hold a dash, so all the
+// bytes are random.
+func randomString(t *testing.T, dash bool) (Token, string) {
+	t.Helper()
+	for {
+		var token Token
+		if _, err := rand.Read(token[:]); err != nil {
+			t.Fatal(err)
+		}
+		if s := token.String(); strings.Contains(s, "-") == dash {
+			return token, s
+		}
+	}
+}
+
+func TestParseLoose(t *testing.T) {
+	token, s := randomString(t, true)
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"plain", s},
+		{"surrounding whitespace", " \t" + s + "\n"},
+		{"spaces", s[:8] + " " + s[8:16] + "  " + s[16:]},
+		{"dots and spaces", s[:4] + ". " + s[4:]},
+		{"dash groups", s[:10] + "-" + s[10:20] + "-" + s[20:30] + "-" + s[30:]},
+		{"dash groups and spaces", " " + s[:16] + " - " + s[16:] + " "},
+		{"no-break spaces", "\u00a0" + s[:16] + "\u00a0" + s[16:] + "\u3000"},
+		{"zero-width spaces", "\ufeff" + s[:8] + "\u200b" + s[8:] + "\u00ad"},
+		{"en dash groups", strings.Join([]string{s[:8], s[8:16], s[16:24], s[24:]}, "\u2013")},
+		{"fullwidth", strings.Map(func(r rune) rune { return r + 0xFEE0 }, s)},
+		{"hex", token.Hex()},
+		{"uppercase hex", " " + strings.ToUpper(token.Hex()) + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLoose(tt.in)
+			if err != nil || got != token {
+				t.Errorf("ParseLoose(%q) = %v, %v, want %v", tt.in, got, err, token)
+			}
+		})
+	}
+}
+
+func TestParseLooseInvalid(t *testing.T) {
+	// A token without dash, so that the inserted one is the only one.
+	_, s := randomString(t, false)
+	for _, in := range []string{
+		"",
+		s[:31],
+		s + "x",
+		// Dashes which are not at group boundaries.
+		s[:3] + "-" + s[3:],
+		// '_' is not a separator.
+		s[:16] + "_" + s[16:],
+	} {
+		if _, err := ParseLoose(in); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("ParseLoose(%q) err = %v, want %v", in, err, ErrInvalidToken)
+		}
+	}
+	if got, err := ParseLooseSeparators(s[:16]+"_"+s[16:], "_"); err != nil || got != MustFromString(s) {
+		t.Errorf("ParseLooseSeparators() = %v, %v, want %v", got, err, s)
+	}
+}
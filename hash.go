@@ -0,0 +1,28 @@
+package xtoken
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// wyhash secrets, see https://github.com/wangyi-fudan/wyhash.
+const (
+	wyp0 = 0xa0761d6478bd642f
+	wyp1 = 0xe7037ed1a0b428db
+)
+
+// wymix multiplies a and b into 128 bits and folds them.
+func wymix(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+// Hash64 returns a wyhash-style 64-bit hash of the 12 raw bytes of the token
+// with seed. It's fast and well distributed, e.g. to pick one of n shards with
+// Hash64(seed) % n, but it's not a cryptographic hash.
+func (token Token) Hash64(seed uint64) uint64 {
+	seed ^= wymix(seed^wyp0, wyp1)
+	a := uint64(binary.LittleEndian.Uint32(token[0:4]))<<32 | uint64(binary.LittleEndian.Uint32(token[4:8]))
+	b := uint64(binary.LittleEndian.Uint32(token[8:12]))<<32 | uint64(binary.LittleEndian.Uint32(token[4:8]))
+	return wymix(wyp1^rawLen, wymix(a^wyp1, b^seed))
+}
@@ -0,0 +1,46 @@
+package xtoken
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHash64(t *testing.T) {
+	a, b := New(), New()
+	if a.Hash64(1) != a.Hash64(1) {
+		t.Error("Hash64() is not deterministic")
+	}
+	if a.Hash64(1) == b.Hash64(1) {
+		t.Error("Hash64() of different tokens are equal")
+	}
+	if a.Hash64(1) == a.Hash64(2) {
+		t.Error("Hash64() with different seeds are equal")
+	}
+}
+
+func TestHash64Distribution(t *testing.T) {
+	const n, buckets = 4 << 20, 256
+	var counts [buckets]int
+	now := time.Unix(1700000000, 0)
+	for i := 0; i < n; i++ {
+		// Sequential tokens of one process only differ by their counter.
+		token, err := NewFromParts(now, [3]byte{1, 2, 3}, 4, uint32(i)&maxCounter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[token.Hash64(42)%buckets]++
+	}
+	want := n / buckets
+	for i, c := range counts {
+		if dev := float64(c-want) / float64(want); dev < -0.05 || dev > 0.05 {
+			t.Errorf("bucket %d has %d tokens, want %d ± 5%%", i, c, want)
+		}
+	}
+}
+
+func BenchmarkHash64(b *testing.B) {
+	token := New()
+	for i := 0; i < b.N; i++ {
+		_ = token.Hash64(uint64(i))
+	}
+}
@@ -0,0 +1,41 @@
+package xtoken
+
+import (
+	"encoding/hex"
+)
+
+const hexEncodedLen = 24 // hex encoded len
+
+// hexDec is the decoding map for hex, both cases are accepted.
+var hexDec [256]byte
+
+func init() {
+	for i := 0; i < len(hexDec); i++ {
+		hexDec[i] = 0xFF
+	}
+	for i := 0; i < 10; i++ {
+		hexDec['0'+i] = byte(i)
+	}
+	for i := 0; i < 6; i++ {
+		hexDec['a'+i] = byte(10 + i)
+		hexDec['A'+i] = byte(10 + i)
+	}
+}
+
+// Hex returns the 24 chars lowercase hex representation of the token.
+func (token Token) Hex() string {
+	return hex.EncodeToString(token[:])
+}
+
+// FromHex reads a Token from its 24 chars hex representation, both cases are
+// accepted.
+func FromHex(s string) (Token, error) {
+	var token Token
+	if err := checkText(s, hexEncodedLen, &hexDec); err != nil {
+		return token, err
+	}
+	for i := 0; i < rawLen; i++ {
+		token[i] = hexDec[s[2*i]]<<4 | hexDec[s[2*i+1]]
+	}
+	return token, nil
+}
@@ -0,0 +1,53 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHex(t *testing.T) {
+	for _, v := range IDs {
+		s := v.token.Hex()
+		if len(s) != hexEncodedLen {
+			t.Errorf("len(Hex()) = %d, want %d", len(s), hexEncodedLen)
+		}
+		got, err := FromHex(s)
+		if err != nil {
+			t.Fatalf("FromHex(%q) err: %v", s, err)
+		}
+		if got != v.token {
+			t.Errorf("FromHex(%q) = %x, want %x", s, got[:], v.token[:])
+		}
+	}
+	if got, want := IDs[0].token.Hex(), "4d88e15b60f486e428412dc9"; got != want {
+		t.Errorf("Hex() = %q, want %q", got, want)
+	}
+	if got, want := nilToken.Hex(), "000000000000000000000000"; got != want {
+		t.Errorf("Hex() = %q, want %q", got, want)
+	}
+	if got, err := FromHex("4D88e15B60F486E428412dc9"); err != nil || got != IDs[0].token {
+		t.Errorf("FromHex() mixed case = %x, %v, want %x", got[:], err, IDs[0].token[:])
+	}
+}
+
+func TestFromHexInvalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"", &InvalidLengthError{Got: 0, Want: hexEncodedLen}},
+		{"4d88e15b60f486e428412dc", &InvalidLengthError{Got: 23, Want: hexEncodedLen}},
+		{"4d88e15b60f486e428412dc9a", &InvalidLengthError{Got: 25, Want: hexEncodedLen}},
+		{"4d88e15b60f486e428412dcz", &InvalidCharacterError{Char: 'z', Pos: 23}},
+		{"0x88e15b60f486e428412dc9", &InvalidCharacterError{Char: 'x', Pos: 1}},
+	}
+	for _, tt := range tests {
+		_, err := FromHex(tt.s)
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromHex(%q) err = %v, want %v", tt.s, err, ErrInvalidToken)
+		}
+		if err == nil || err.Error() != tt.err.Error() {
+			t.Errorf("FromHex(%q) err = %v, want %v", tt.s, err, tt.err)
+		}
+	}
+}
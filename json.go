@@ -0,0 +1,29 @@
+package xtoken
+
+// MarshalJSON implements json.Marshaler, the token is serialized as its string
+// representation, the nil token as null.
+func (token Token) MarshalJSON() ([]byte, error) {
+	if token.IsZero() {
+		return []byte("null"), nil
+	}
+	b := make([]byte, 0, encodedLen+2)
+	b = append(b, '"')
+	b = StdEncoding.appendEncode(b, token)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON string holding
+// a token, null and the empty string are the nil token.
+func (token *Token) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" || s == `""` {
+		*token = nilToken
+		return nil
+	}
+	// The alphabet has no chars escaped by JSON, so the quotes can simply be
+	// trimmed, anything else is rejected by UnmarshalText.
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return ErrInvalidToken
+	}
+	return token.UnmarshalText(b[1 : len(b)-1])
+}
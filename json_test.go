@@ -0,0 +1,57 @@
+package xtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type jsonType struct {
+	Token  Token  `json:"token"`
+	TokenP *Token `json:"token_p,omitempty"`
+}
+
+func TestJSON(t *testing.T) {
+	token := New()
+	in := jsonType{Token: token, TokenP: &token}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() err: %v", err)
+	}
+	var out jsonType
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal(%s) err: %v", b, err)
+	}
+	if out.Token != token || out.TokenP == nil || *out.TokenP != token {
+		t.Errorf("json.Unmarshal(%s) = %+v, want %+v", b, out, in)
+	}
+}
+
+func TestJSONNil(t *testing.T) {
+	b, err := json.Marshal(jsonType{})
+	if err != nil {
+		t.Fatalf("json.Marshal() err: %v", err)
+	}
+	if want := `{"token":null}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+	for _, s := range []string{`{"token":null}`, `{"token":""}`} {
+		out := jsonType{Token: New()}
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			t.Fatalf("json.Unmarshal(%s) err: %v", s, err)
+		}
+		if !out.Token.IsZero() {
+			t.Errorf("json.Unmarshal(%s) = %v, want the nil token", s, out.Token)
+		}
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	s := New().String()
+	for _, in := range []string{`1`, `"` + s, s, `"` + s[1:] + `"`, `"!` + s[1:] + `"`} {
+		var token Token
+		if err := token.UnmarshalJSON([]byte(in)); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("UnmarshalJSON(%s) err = %v, want ErrInvalidToken", in, err)
+		}
+	}
+}
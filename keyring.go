@@ -0,0 +1,119 @@
+package xtoken
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// ErrUnknownKey is returned when a token names a key which isn't in the
+	// Keyring, or whose grace period is over.
+	ErrUnknownKey strErr = "unknown Token key"
+)
+
+// Keyring holds the keys of a Signer or an Encryptor with their 1-byte id, so
+// that keys rotate without invalidating the outstanding tokens: the signed and
+// sealed strings embed the id of their key, and are verified with it after a
+// newer key became current. It's safe for concurrent use.
+type Keyring struct {
+	grace time.Duration
+
+	mu      sync.RWMutex
+	keys    map[byte]*ringKey
+	current *ringKey
+}
+
+// ringKey is a key of a Keyring.
+type ringKey struct {
+	id      byte
+	key     []byte
+	retired time.Time // zero while current
+
+	once sync.Once
+	aead cipher.AEAD
+	err  error
+}
+
+// cipher returns the AES-GCM cipher of the key, built on first use.
+func (k *ringKey) cipher() (cipher.AEAD, error) {
+	k.once.Do(func() {
+		k.aead, k.err = newGCM(k.key)
+	})
+	return k.aead, k.err
+}
+
+// KeyringOption configures a Keyring.
+type KeyringOption func(k *Keyring)
+
+// WithGracePeriod makes the keys replaced by Rotate verify their tokens for d
+// only, rather than until Remove.
+func WithGracePeriod(d time.Duration) KeyringOption {
+	return func(k *Keyring) {
+		k.grace = d
+	}
+}
+
+// NewKeyring returns a Keyring configured with opts, whose current key is key
+// with id.
+func NewKeyring(id byte, key []byte, opts ...KeyringOption) *Keyring {
+	k := &Keyring{keys: make(map[byte]*ringKey)}
+	for _, opt := range opts {
+		opt(k)
+	}
+	k.current = &ringKey{id: id, key: append([]byte(nil), key...)}
+	k.keys[id] = k.current
+	return k
+}
+
+// Rotate makes key with id the current key, new tokens are signed or sealed
+// with it. The previous keys keep verifying their tokens, until their grace
+// period is over or they are removed. Ids can't be reused, it returns an error
+// when id is already in the Keyring.
+func (k *Keyring) Rotate(id byte, key []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("xtoken: key id %d already in the keyring", id)
+	}
+	k.current.retired = timeNow()
+	k.current = &ringKey{id: id, key: append([]byte(nil), key...)}
+	k.keys[id] = k.current
+	return nil
+}
+
+// Remove removes the key with id, its tokens don't verify anymore. It reports
+// whether the key was removed, the current key can't be.
+func (k *Keyring) Remove(id byte) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok || id == k.current.id {
+		return false
+	}
+	delete(k.keys, id)
+	return true
+}
+
+// currentKey returns the key to sign or seal new tokens with.
+func (k *Keyring) currentKey() *ringKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+// lookup returns the key with id, or ErrUnknownKey when it isn't in the
+// Keyring or its grace period is over.
+func (k *Keyring) lookup(id byte) (*ringKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[id]
+	retired := time.Time{}
+	if ok {
+		retired = key.retired
+	}
+	k.mu.RUnlock()
+	if !ok || k.grace > 0 && !retired.IsZero() && timeNow().Sub(retired) > k.grace {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
@@ -0,0 +1,104 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyringSigner(t *testing.T) {
+	ring := NewKeyring(1, []byte("first key"))
+	s := NewKeyringSigner(ring)
+	first := s.New()
+	if want := encodedLen + 12; len(first) != want {
+		t.Errorf("len(New()) = %d, want %d", len(first), want)
+	}
+	if err := ring.Rotate(2, []byte("second key")); err != nil {
+		t.Fatalf("Rotate() err: %v", err)
+	}
+	second := s.New()
+	for _, str := range []string{first, second} {
+		if _, err := s.Verify(str); err != nil {
+			t.Errorf("Verify(%q) err: %v", str, err)
+		}
+	}
+	// The signature of the second key doesn't verify with the first one.
+	if _, err := NewKeyringSigner(NewKeyring(1, []byte("second key"))).Verify(second); err != ErrUnknownKey {
+		t.Errorf("Verify() with a keyring missing the key err = %v, want %v", err, ErrUnknownKey)
+	}
+	if _, err := NewKeyringSigner(NewKeyring(2, []byte("other key"))).Verify(second); err != ErrBadSignature {
+		t.Errorf("Verify() with another key err = %v, want %v", err, ErrBadSignature)
+	}
+
+	if !ring.Remove(1) {
+		t.Fatal("Remove(1) = false, want true")
+	}
+	if _, err := s.Verify(first); err != ErrUnknownKey {
+		t.Errorf("Verify() with a removed key err = %v, want %v", err, ErrUnknownKey)
+	}
+	if ring.Remove(2) {
+		t.Error("Remove() of the current key = true, want false")
+	}
+	if _, err := s.Verify(first[:len(first)-1]); !errors.Is(err, ErrBadLength) {
+		t.Errorf("Verify() of a short input err = %v, want %v", err, ErrBadLength)
+	}
+}
+
+func TestKeyringRotateDuplicate(t *testing.T) {
+	ring := NewKeyring(1, []byte("first key"))
+	if err := ring.Rotate(1, []byte("second key")); err == nil {
+		t.Error("Rotate() with the current id err = nil")
+	}
+}
+
+func TestKeyringGracePeriod(t *testing.T) {
+	now := time.Now()
+	withNow(t, now)
+	ring := NewKeyring(1, []byte("first key"), WithGracePeriod(time.Hour))
+	s := NewKeyringSigner(ring)
+	first := s.New()
+	if err := ring.Rotate(2, []byte("second key")); err != nil {
+		t.Fatalf("Rotate() err: %v", err)
+	}
+	second := s.New()
+
+	withNow(t, now.Add(30*time.Minute))
+	if _, err := s.Verify(first); err != nil {
+		t.Errorf("Verify() within the grace period err: %v", err)
+	}
+	withNow(t, now.Add(2*time.Hour))
+	if _, err := s.Verify(first); err != ErrUnknownKey {
+		t.Errorf("Verify() after the grace period err = %v, want %v", err, ErrUnknownKey)
+	}
+	// The current key has no grace period.
+	if _, err := s.Verify(second); err != nil {
+		t.Errorf("Verify() with the current key err: %v", err)
+	}
+}
+
+func TestKeyringEncryptor(t *testing.T) {
+	ring := NewKeyring(1, []byte("0123456789abcdef"))
+	e, err := NewKeyringEncryptor(ring)
+	if err != nil {
+		t.Fatalf("NewKeyringEncryptor() err: %v", err)
+	}
+	first := e.Seal([]byte("user:1"))
+	if err := ring.Rotate(2, []byte("fedcba9876543210fedcba9876543210")); err != nil {
+		t.Fatalf("Rotate() err: %v", err)
+	}
+	second := e.Seal([]byte("user:2"))
+	for i, s := range []string{first, second} {
+		if _, payload, err := e.Open(s); err != nil || !bytes.Equal(payload, []byte{'u', 's', 'e', 'r', ':', byte('1' + i)}) {
+			t.Errorf("Open(%q) = %q, %v", s, payload, err)
+		}
+	}
+	ring.Remove(1)
+	if _, _, err := e.Open(first); err != ErrUnknownKey {
+		t.Errorf("Open() with a removed key err = %v, want %v", err, ErrUnknownKey)
+	}
+
+	if _, err := NewKeyringEncryptor(NewKeyring(1, []byte("short"))); err == nil {
+		t.Error("NewKeyringEncryptor() with a 5 bytes key err = nil")
+	}
+}
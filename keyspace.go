@@ -0,0 +1,58 @@
+package xtoken
+
+import (
+	"slices"
+	"strings"
+)
+
+// Keyspace maps tokens to cache keys, such as Redis keys, made of Prefix and
+// the CanonicalString of the token: unlike String, a token always maps to the
+// same key. The zero value has no prefix.
+//
+//	sessions := xtoken.Keyspace{Prefix: "session:"}
+//	err := rdb.Set(ctx, sessions.Key(token), data, time.Hour).Err()
+type Keyspace struct {
+	Prefix string
+}
+
+// Key returns the key of token.
+func (k Keyspace) Key(token Token) string {
+	return string(k.AppendKey(make([]byte, 0, len(k.Prefix)+encodedLen), token))
+}
+
+// AppendKey appends the key of token to b.
+func (k Keyspace) AppendKey(b []byte, token Token) []byte {
+	b = append(b, k.Prefix...)
+	n := len(b)
+	b = slices.Grow(b, encodedLen)[:n+encodedLen]
+	StdEncoding.encodeOrder(b[n:], token[:], valuePositions[:])
+	return b
+}
+
+// Parse reads the token of a key, e.g. one returned by a SCAN. It returns
+// ErrPrefixMismatch when key doesn't start with Prefix and ErrInvalidToken
+// when the rest isn't a token.
+func (k Keyspace) Parse(key string) (Token, error) {
+	s, ok := strings.CutPrefix(key, k.Prefix)
+	if !ok {
+		return nilToken, ErrPrefixMismatch
+	}
+	return FromString(s)
+}
+
+// Pattern returns the glob-style pattern matching the keys of k, for the
+// MATCH option of SCAN: Prefix, with its special chars escaped, followed by *.
+func (k Keyspace) Pattern() string {
+	var b strings.Builder
+	for i := 0; i < len(k.Prefix); i++ {
+		switch c := k.Prefix[i]; c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+			fallthrough
+		default:
+			b.WriteByte(k.Prefix[i])
+		}
+	}
+	b.WriteByte('*')
+	return b.String()
+}
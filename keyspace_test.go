@@ -0,0 +1,51 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKeyspace(t *testing.T) {
+	sessions := Keyspace{Prefix: "session:"}
+	for _, v := range IDs {
+		key := sessions.Key(v.token)
+		if want := "session:" + v.token.CanonicalString(); key != want {
+			t.Errorf("Key() = %q, want %q", key, want)
+		}
+		if b := sessions.AppendKey([]byte("x"), v.token); string(b) != "x"+key {
+			t.Errorf("AppendKey() = %q, want %q", b, "x"+key)
+		}
+		if got, err := sessions.Parse(key); err != nil || got != v.token {
+			t.Errorf("Parse(%q) = %v, %v, want %v", key, got, err, v.token)
+		}
+	}
+	token := New()
+	if _, err := sessions.Parse("user:" + token.String()); err != ErrPrefixMismatch {
+		t.Errorf("Parse() of another prefix err = %v, want %v", err, ErrPrefixMismatch)
+	}
+	if _, err := sessions.Parse("session:garbage"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Parse() of garbage err = %v, want %v", err, ErrInvalidToken)
+	}
+	// Keys are read back whatever the representation.
+	if got, err := sessions.Parse("session:" + token.String()); err != nil || got != token {
+		t.Errorf("Parse() of a String = %v, %v, want %v", got, err, token)
+	}
+	if got, err := (Keyspace{}).Parse(token.String()); err != nil || got != token {
+		t.Errorf("Parse() without prefix = %v, %v, want %v", got, err, token)
+	}
+}
+
+func TestKeyspacePattern(t *testing.T) {
+	tests := []struct {
+		prefix, want string
+	}{
+		{"", "*"},
+		{"session:", "session:*"},
+		{"a*b?[c]\\:", `a\*b\?\[c\]\\:*`},
+	}
+	for _, tt := range tests {
+		if got := (Keyspace{Prefix: tt.prefix}).Pattern(); got != tt.want {
+			t.Errorf("Pattern() for %q = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package xtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+const redactedVisible = 4 // chars shown on each side of a redacted token
+
+var (
+	// logRedacted makes LogValue log the redacted form of tokens.
+	logRedacted atomic.Bool
+
+	// logDetailed makes LogValue log the components of tokens as well.
+	logDetailed atomic.Bool
+
+	// redactKey keys the hash redacted tokens show, it's drawn once per
+	// process so that they can't be matched against guessed tokens.
+	redactKey = sync.OnceValue(func() []byte {
+		key := make([]byte, sha256.Size)
+		if err := readRand(key); err != nil {
+			panic(err)
+		}
+		return key
+	})
+)
+
+// SetLogRedacted sets whether Token.LogValue logs the redacted form of tokens
+// instead of their full string representation, which is the default.
+func SetLogRedacted(redacted bool) {
+	logRedacted.Store(redacted)
+}
+
+// SetLogDetailed sets whether Token.LogValue logs tokens as a group holding
+// their string representation under "id" and their components under "time",
+// "machine", "pid" and "counter", instead of a single string, which is the
+// default. When the redacted form is logged, only the time is added since the
+// other components make up the rest of the token.
+func SetLogDetailed(detailed bool) {
+	logDetailed.Store(detailed)
+}
+
+// LogValue implements slog.LogValuer, tokens are logged as a string attribute,
+// or a group with SetLogDetailed.
+func (token Token) LogValue() slog.Value {
+	redacted, detailed := logRedacted.Load(), logDetailed.Load()
+	id := token.String()
+	if redacted {
+		id = token.Redacted()
+	}
+	if !detailed {
+		return slog.StringValue(id)
+	}
+	t, machine, pid, counter := token.Parts()
+	if redacted {
+		return slog.GroupValue(slog.String("id", id), slog.Time("time", t))
+	}
+	return slog.GroupValue(
+		slog.String("id", id),
+		slog.Time("time", t),
+		slog.String("machine", hex.EncodeToString(machine[:])),
+		slog.Int("pid", int(pid)),
+		slog.Int("counter", int(counter)),
+	)
+}
+
+// Redacted returns a masked form of the token for logs, showing only the first
+// and last 4 chars, e.g. "aB3d…wXyZ". The chars are those of a keyed hash of
+// the token, not of its string representation, whose first and last chars
+// barely vary between tokens. Unlike String it's stable across calls, so
+// redacted tokens can still be correlated within the process.
+func (token Token) Redacted() string {
+	return Redactor{Prefix: redactedVisible, Suffix: redactedVisible}.Redact(token)
+}
+
+// Redactor masks tokens for logs like Token.Redacted, with Prefix chars shown
+// at the start and Suffix at the end, e.g. Redactor{Prefix: 6} gives
+// "aB3dEf…". The zero Redactor only logs the mask. Prefix and Suffix are
+// capped so that at least half of the 32 chars are always masked.
+type Redactor struct {
+	Prefix int // chars shown at the start
+	Suffix int // chars shown at the end
+	// Mask replaces the hidden chars, it defaults to "…".
+	Mask string
+	// Key keys the hash the shown chars are taken from, it defaults to a
+	// random key of the process. Share a Key between services to correlate
+	// their redacted tokens, and keep it as secret as the tokens.
+	Key []byte
+}
+
+// Redact returns the masked form of token, it's stable across calls.
+func (r Redactor) Redact(token Token) string {
+	key := r.Key
+	if key == nil {
+		key = redactKey()
+	}
+	text := redactedText(key, token)
+	prefix := min(max(r.Prefix, 0), encodedLen/2)
+	suffix := min(max(r.Suffix, 0), encodedLen/2-prefix)
+	mask := r.Mask
+	if mask == "" {
+		mask = "…"
+	}
+	return string(text[:prefix]) + mask + string(text[encodedLen-suffix:])
+}
+
+// redactedText returns 32 chars of the alphabet holding 192 bits of the
+// HMAC-SHA256 of token keyed with key.
+func redactedText(key []byte, token Token) [encodedLen]byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(token[:])
+	sum := mac.Sum(nil)
+	var text [encodedLen]byte
+	for i := range text {
+		bit := i * 6
+		v := (uint16(sum[bit/8])<<8 | uint16(sum[bit/8+1])) >> (10 - bit%8)
+		text[i] = encoding[v&encodingIdxMax]
+	}
+	return text
+}
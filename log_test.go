@@ -0,0 +1,186 @@
+package xtoken
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestRedacted(t *testing.T) {
+	token := New()
+	r := token.Redacted()
+	if n := utf8.RuneCountInString(r); n != 2*redactedVisible+1 {
+		t.Errorf("Redacted() = %q, want %d runes", r, 2*redactedVisible+1)
+	}
+	if !strings.Contains(r, "…") {
+		t.Errorf("Redacted() = %q, want a masked middle", r)
+	}
+	for i := 0; i < 10; i++ {
+		if got := token.Redacted(); got != r {
+			t.Fatalf("Redacted() = %q, want stable %q", got, r)
+		}
+	}
+	if New().Redacted() == r {
+		t.Errorf("Redacted() of two tokens are equal")
+	}
+}
+
+func TestRedactedDistinct(t *testing.T) {
+	// Consecutive tokens only differ by their counter, the redacted form of
+	// each must still be its own: 48 bits make a collision unlikely.
+	seen := make(map[string]Token)
+	for _, token := range NewBatch(100000) {
+		r := token.Redacted()
+		if other, ok := seen[r]; ok {
+			t.Fatalf("Redacted() of %v and %v = %q", other, token, r)
+		}
+		seen[r] = token
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	token := New()
+	// The chars shown are those of the keyed hash.
+	text := redactedText(redactKey(), token)
+	full := string(text[:])
+	tests := []struct {
+		r    Redactor
+		want string
+	}{
+		{Redactor{Prefix: 4, Suffix: 4}, token.Redacted()},
+		{Redactor{Prefix: 6}, full[:6] + "…"},
+		{Redactor{Suffix: 2, Mask: "***"}, "***" + full[encodedLen-2:]},
+		{Redactor{}, "…"},
+		{Redactor{Prefix: -1, Suffix: -1}, "…"},
+		// At least half of the chars are masked.
+		{Redactor{Prefix: 40, Suffix: 40}, full[:encodedLen/2] + "…"},
+		{Redactor{Prefix: 10, Suffix: 10}, full[:10] + "…" + full[encodedLen-6:]},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Redact(token); got != tt.want {
+			t.Errorf("%+v.Redact() = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestRedactorKey(t *testing.T) {
+	token := New()
+	a := Redactor{Prefix: 4, Suffix: 4, Key: []byte("key")}
+	b := Redactor{Prefix: 4, Suffix: 4, Key: []byte("key")}
+	if a.Redact(token) != b.Redact(token) {
+		t.Errorf("Redact() with the same key = %q and %q", a.Redact(token), b.Redact(token))
+	}
+	b.Key = []byte("other")
+	if a.Redact(token) == b.Redact(token) {
+		t.Errorf("Redact() with other keys are both %q", a.Redact(token))
+	}
+	if a.Redact(token) == token.Redacted() {
+		t.Errorf("Redact() with a key = %q, the same as without", token.Redacted())
+	}
+}
+
+func TestRedactorDistinct(t *testing.T) {
+	r := Redactor{Prefix: 6, Key: []byte("key")}
+	seen := make(map[string]Token)
+	for _, token := range NewBatch(100000) {
+		s := r.Redact(token)
+		if other, ok := seen[s]; ok {
+			t.Fatalf("Redact() of %v and %v = %q", other, token, s)
+		}
+		seen[s] = token
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	defer SetLogRedacted(false)
+	token := New()
+	for _, redacted := range []bool{false, true} {
+		SetLogRedacted(redacted)
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("request", "token", token)
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("json.Unmarshal(%q) err: %v", buf.String(), err)
+		}
+		s, ok := record["token"].(string)
+		if !ok {
+			t.Fatalf("token attribute = %#v, want a string", record["token"])
+		}
+		if redacted {
+			if s != token.Redacted() {
+				t.Errorf("redacted token attribute = %q, want %q", s, token.Redacted())
+			}
+			continue
+		}
+		if got, err := FromString(s); err != nil || got != token {
+			t.Errorf("token attribute = %q, want an encoding of %v", s, token)
+		}
+	}
+}
+
+func TestLogValueDetailed(t *testing.T) {
+	defer SetLogRedacted(false)
+	defer SetLogDetailed(false)
+	SetLogDetailed(true)
+	token, err := NewFromParts(time.Unix(1700000000, 0), [3]byte{0x01, 0x02, 0x03}, 4242, 0x0A0B0C)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, redacted := range []bool{false, true} {
+		SetLogRedacted(redacted)
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("request", "token", token)
+
+		var record struct {
+			Token map[string]interface{} `json:"token"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("json.Unmarshal(%q) err: %v", buf.String(), err)
+		}
+		got := record.Token
+		if s, err := time.Parse(time.RFC3339, got["time"].(string)); err != nil || !s.Equal(token.Time()) {
+			t.Errorf("time attribute = %v, want %v", got["time"], token.Time())
+		}
+		if redacted {
+			if got["id"] != token.Redacted() || len(got) != 2 {
+				t.Errorf("redacted token group = %v, want only id %q and time", got, token.Redacted())
+			}
+			continue
+		}
+		if id, err := FromString(got["id"].(string)); err != nil || id != token {
+			t.Errorf("id attribute = %v, want an encoding of %v", got["id"], token)
+		}
+		if got["machine"] != "010203" || got["pid"] != 4242.0 || got["counter"] != float64(0x0A0B0C) {
+			t.Errorf("token group = %v, want machine 010203, pid 4242 and counter %d", got, 0x0A0B0C)
+		}
+	}
+}
+
+func TestLogValueDetailedRedactedDistinct(t *testing.T) {
+	defer SetLogRedacted(false)
+	defer SetLogDetailed(false)
+	SetLogDetailed(true)
+	SetLogRedacted(true)
+	// Tokens of the same second only differ by their counter, their groups
+	// must still tell them apart.
+	seen := make(map[string]Token)
+	for _, token := range NewBatch(10000) {
+		var id string
+		for _, attr := range token.LogValue().Group() {
+			if attr.Key == "id" {
+				id = attr.Value.String()
+			}
+		}
+		if other, ok := seen[id]; ok {
+			t.Fatalf("LogValue() id of %v and %v = %q", other, token, id)
+		}
+		seen[id] = token
+	}
+}
@@ -0,0 +1,110 @@
+package xtoken
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+const (
+	longEncodedLen = 26 // TokenLong string encoded len
+	longRawLen     = 16 // TokenLong binary raw len
+)
+
+// TokenLong is a variant of Token with 4 more bytes of entropy, for
+// identifiers minted across many hosts or containers which may share a
+// machine id:
+//   - the 12 bytes of a Token, followed by
+//   - 4 bytes from crypto/rand.
+//
+// Its string form is a 26 chars base32hex encoding at fixed positions.
+type TokenLong [longRawLen]byte
+
+var nilTokenLong TokenLong
+
+// NewLong generates a globally unique TokenLong
+func NewLong() TokenLong {
+	return Extend(New())
+}
+
+// NewLongWithTime generates a globally unique TokenLong with the passed in time
+func NewLongWithTime(t time.Time) TokenLong {
+	return Extend(NewWithTime(t))
+}
+
+// Extend returns a TokenLong made of token followed by 4 bytes from
+// crypto/rand, TokenLong.Truncate returns token back.
+func Extend(token Token) TokenLong {
+	var long TokenLong
+	copy(long[:], token[:])
+	if _, err := rand.Read(long[rawLen:]); err != nil {
+		panic(fmt.Errorf("xtoken: cannot generate random number: %v", err))
+	}
+	return long
+}
+
+// Truncate returns the Token made of the first 12 bytes of the token, it
+// drops the entropy.
+func (token TokenLong) Truncate() Token {
+	return Token(token[:rawLen])
+}
+
+// Time returns the timestamp part of the token.
+func (token TokenLong) Time() time.Time {
+	return token.Truncate().Time()
+}
+
+// Machine returns the 3-byte machine id part of the token.
+func (token TokenLong) Machine() []byte {
+	return token.Truncate().Machine()
+}
+
+// Pid returns the process id part of the token.
+func (token TokenLong) Pid() uint16 {
+	return token.Truncate().Pid()
+}
+
+// Counter returns the incrementing value part of the token.
+func (token TokenLong) Counter() int32 {
+	return token.Truncate().Counter()
+}
+
+// Entropy returns the 4 random bytes of the token.
+func (token TokenLong) Entropy() [4]byte {
+	return [4]byte(token[rawLen:])
+}
+
+// FromStringLong reads a TokenLong from its string representation
+func FromStringLong(s string) (TokenLong, error) {
+	var token TokenLong
+	if err := checkText(s, longEncodedLen, &sortableDec); err != nil {
+		return token, err
+	}
+	if !decodeBase32(token[:], s, &sortableDec) {
+		return nilTokenLong, ErrInconsistentToken
+	}
+	return token, nil
+}
+
+// String returns a 26 chars base32hex lowercased representation of the token (char set is 0-9, a-v).
+func (token TokenLong) String() string {
+	text := make([]byte, longEncodedLen)
+	encodeBase32(text, token[:], sortableEncoding)
+	return string(text)
+}
+
+// IsZero Returns true if this is a "nil" TokenLong
+func (token TokenLong) IsZero() bool {
+	return token == nilTokenLong
+}
+
+// Bytes returns the byte array representation of the token
+func (token TokenLong) Bytes() []byte {
+	return token[:]
+}
+
+// Compare returns an integer comparing two tokens. It behaves just like `bytes.Compare`.
+func (token TokenLong) Compare(other TokenLong) int {
+	return bytes.Compare(token[:], other[:])
+}
@@ -0,0 +1,84 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewLongWithTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := NewLongWithTime(now)
+	if got := token.Time(); !got.Equal(now) {
+		t.Errorf("Time() = %v, want %v", got, now)
+	}
+	short := token.Truncate()
+	if !bytes.Equal(token.Machine(), short.Machine()) || token.Pid() != short.Pid() || token.Counter() != short.Counter() {
+		t.Errorf("accessors of %x don't match the ones of %x", token[:], short[:])
+	}
+	if got, want := token.Pid(), CurrentPid(); got != want {
+		t.Errorf("Pid() = %d, want %d", got, want)
+	}
+	if e := token.Entropy(); !bytes.Equal(e[:], token[rawLen:]) {
+		t.Errorf("Entropy() = %x, want %x", e, token[rawLen:])
+	}
+
+	s := token.String()
+	if len(s) != longEncodedLen {
+		t.Errorf("len(String()) = %d, want %d", len(s), longEncodedLen)
+	}
+	if s != token.String() {
+		t.Errorf("String() is not deterministic")
+	}
+	got, err := FromStringLong(s)
+	if err != nil {
+		t.Fatalf("FromStringLong(%q) err: %v", s, err)
+	}
+	if got != token {
+		t.Errorf("FromStringLong(%q) = %x, want %x", s, got[:], token[:])
+	}
+}
+
+func TestExtendTruncate(t *testing.T) {
+	token := New()
+	long := Extend(token)
+	if got := long.Truncate(); got != token {
+		t.Errorf("Truncate() = %v, want %v", got, token)
+	}
+	if other := Extend(token); other == long {
+		t.Errorf("Extend() twice = %x, want different entropy", other[:])
+	}
+}
+
+func TestNewLongUniqueness(t *testing.T) {
+	n := 100000
+	seen := make(map[TokenLong]struct{}, n)
+	for i := 0; i < n; i++ {
+		token := NewLong()
+		if _, ok := seen[token]; ok {
+			t.Fatalf("NewLong() generated a duplicate after %d tokens", i)
+		}
+		seen[token] = struct{}{}
+	}
+}
+
+func TestFromStringLongInvalid(t *testing.T) {
+	valid := NewLong().String()
+	for _, s := range []string{
+		"",
+		valid[1:],
+		valid + "0",
+		"W" + valid[1:],
+		valid[:longEncodedLen-1] + "1", // padding bits set
+		New().String(),
+	} {
+		if _, err := FromStringLong(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromStringLong(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+	var zero TokenLong
+	if !zero.IsZero() {
+		t.Error("IsZero() = false, want true")
+	}
+}
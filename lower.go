@@ -0,0 +1,47 @@
+package xtoken
+
+const (
+	lowerEncodedLen = 20 // lowercase string encoded len
+
+	// lowerEncoding is the lowercase Crockford base32 alphabet, without the
+	// look-alike i, l, o and u.
+	lowerEncoding = "0123456789abcdefghjkmnpqrstvwxyz"
+)
+
+// lowerDec is the decoding map for lowerEncoding, it folds uppercase.
+var lowerDec [256]byte
+
+func init() {
+	for i := 0; i < len(lowerDec); i++ {
+		lowerDec[i] = 0xFF
+	}
+	for i := 0; i < len(lowerEncoding); i++ {
+		c := lowerEncoding[i]
+		lowerDec[c] = byte(i)
+		if c >= 'a' && c <= 'z' {
+			lowerDec[c-'a'+'A'] = byte(i)
+		}
+	}
+}
+
+// LowerString returns a 20 chars lowercase representation of the token, for
+// case-insensitive contexts such as DNS labels, bucket names or citext columns
+// (char set is 0-9 and a-z without i, l, o, u). String remains the default.
+func (token Token) LowerString() string {
+	text := make([]byte, lowerEncodedLen)
+	encodeBase32(text, token[:], lowerEncoding)
+	return string(text)
+}
+
+// FromLowerString reads a token from its LowerString representation, uppercase
+// chars are accepted as well.
+func FromLowerString(s string) (Token, error) {
+	var token Token
+	if err := checkText(s, lowerEncodedLen, &lowerDec); err != nil {
+		return token, err
+	}
+	if !decodeBase32(token[:], s, &lowerDec) {
+		return nilToken, ErrInconsistentToken
+	}
+	return token, nil
+}
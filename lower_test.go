@@ -0,0 +1,62 @@
+package xtoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLowerString(t *testing.T) {
+	tokens := []Token{New(), New()}
+	for _, v := range IDs {
+		tokens = append(tokens, v.token)
+	}
+	for _, token := range tokens {
+		s := token.LowerString()
+		if len(s) != lowerEncodedLen {
+			t.Errorf("len(LowerString()) = %d, want %d", len(s), lowerEncodedLen)
+		}
+		if s != strings.ToLower(s) {
+			t.Errorf("LowerString() = %q, want lowercase", s)
+		}
+		for _, in := range []string{s, strings.ToUpper(s)} {
+			got, err := FromLowerString(in)
+			if err != nil {
+				t.Fatalf("FromLowerString(%q) err: %v", in, err)
+			}
+			if got != token {
+				t.Errorf("FromLowerString(%q) = %x, want %x", in, got[:], token[:])
+			}
+		}
+	}
+	if got, want := IDs[0].token.LowerString(), "9p4e2pv0yj3e8a215q4g"; got != want {
+		t.Errorf("LowerString() = %q, want %q", got, want)
+	}
+}
+
+func TestLowerStringEqualFold(t *testing.T) {
+	seen := make(map[string]Token)
+	for i := 0; i < 10000; i++ {
+		token := NewRandom()
+		folded := strings.ToLower(token.LowerString())
+		if other, ok := seen[folded]; ok && other != token {
+			t.Fatalf("%v and %v are equal under case folding", token, other)
+		}
+		seen[folded] = token
+	}
+}
+
+func TestFromLowerStringInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"9p4e2pv0yj3e8a215q4",
+		"9p4e2pv0yj3e8a215q4g0",
+		"9p4e2pv0yj3e8a215q4i", // i is not in the alphabet
+		"9p4e2pv0yj3e8a215q4h", // padding bits set
+		IDs[0].token.SortableString()[:19] + "u",
+	} {
+		if _, err := FromLowerString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromLowerString(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+}
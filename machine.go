@@ -0,0 +1,56 @@
+package xtoken
+
+import (
+	"encoding/hex"
+)
+
+// MachineID is the 3-byte machine id part of a token.
+type MachineID [3]byte
+
+// String returns the 6 chars lowercase hex representation of the machine id.
+func (m MachineID) String() string {
+	return hex.EncodeToString(m[:])
+}
+
+// MarshalText implements encoding.TextMarshaler, it returns the String
+// representation of the machine id.
+func (m MachineID) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// Equal reports whether m and other are the same machine id.
+func (m MachineID) Equal(other MachineID) bool {
+	return m == other
+}
+
+// MachineID returns a copy of the machine id part of the token, New and
+// NewTagged store the tag of the token in its last byte, see Token.Tag.
+func (token Token) MachineID() MachineID {
+	return MachineID(token[4:7])
+}
+
+// CurrentMachineID returns the machine id this process embeds into new
+// untagged tokens, i.e. with a last byte of 0, tagged tokens carry their tag
+// instead.
+func CurrentMachineID() MachineID {
+	m := machineID.Load()
+	return MachineID{m[0], m[1], 0}
+}
+
+// SetMachineID overrides the machine id embedded in subsequently generated
+// tokens with one derived from id, an identity of any length such as the UID
+// of a Kubernetes pod, hashed like the platform machine ids. Only 2 bytes of it
+// are stored in tokens, see NewTagged, so distinct ids may still collide.
+// It's safe to call concurrently with generation, Reinitialize restores the
+// machine id read from the environment and the platform.
+func SetMachineID(id []byte) {
+	machineID.Store(hashMachineID(id))
+}
+
+// WithMachineID sets the machine id embedded in the tokens of the Generator,
+// derived from id like SetMachineID. It defaults to the package-level one.
+func WithMachineID(id []byte) Option {
+	return func(g *Generator) {
+		g.machine = hashMachineID(id)
+	}
+}
@@ -0,0 +1,85 @@
+package xtoken
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMachineID(t *testing.T) {
+	token := MustFromString("VKEoZ3FCqGChUJNBWAaq1WDrXLIpIaPY")
+	m := token.MachineID()
+	if !bytes.Equal(m[:], token.Machine()) {
+		t.Errorf("MachineID() = %x, want %x", m[:], token.Machine())
+	}
+	if got, want := m.String(), fmt.Sprintf("%x", token.Machine()); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !m.Equal(token.MachineID()) || m.Equal(MachineID{m[0] + 1, m[1], m[2]}) {
+		t.Error("Equal() doesn't compare the bytes")
+	}
+}
+
+func TestCurrentMachineID(t *testing.T) {
+	if got, want := New().MachineID(), CurrentMachineID(); !got.Equal(want) {
+		t.Errorf("New().MachineID() = %v, want %v", got, want)
+	}
+	m := machineID.Load()
+	if got := CurrentMachineID(); got[0] != m[0] || got[1] != m[1] || got[2] != 0 {
+		t.Errorf("CurrentMachineID() = %v, want %x00", got, m[:2])
+	}
+}
+
+func TestMachineCopy(t *testing.T) {
+	token := New()
+	want := token
+	m := token.Machine()
+	m[0]++
+	id := token.MachineID()
+	id[1]++
+	if token != want {
+		t.Errorf("token = %x after mutating Machine() and MachineID(), want %x", token[:], want[:])
+	}
+}
+
+func TestSetMachineID(t *testing.T) {
+	saved := machineID.Load()
+	t.Cleanup(func() { machineID.Store(saved) })
+
+	SetMachineID([]byte("pod-a"))
+	a := CurrentMachineID()
+	if got := New().MachineID(); !got.Equal(a) {
+		t.Errorf("New().MachineID() = %v, want %v", got, a)
+	}
+	SetMachineID([]byte("pod-b"))
+	if b := CurrentMachineID(); b.Equal(a) {
+		t.Errorf("CurrentMachineID() = %v for two ids", b)
+	}
+	SetMachineID([]byte("pod-a"))
+	if got := CurrentMachineID(); !got.Equal(a) {
+		t.Errorf("CurrentMachineID() = %v, want %v for the same id", got, a)
+	}
+}
+
+func TestWithMachineID(t *testing.T) {
+	g := NewGenerator(WithMachineID([]byte("pod-a")))
+	want := hashMachineID([]byte("pod-a"))
+	if got := g.New().MachineID(); got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("MachineID() = %v, want %x", got, want[:2])
+	}
+	// The package-level machine id is untouched.
+	if got := New().MachineID(); !got.Equal(CurrentMachineID()) {
+		t.Errorf("New().MachineID() = %v, want %v", got, CurrentMachineID())
+	}
+}
+
+func TestMachineIDEnv(t *testing.T) {
+	t.Setenv(MachineIDEnv, "pod-uid")
+	id, err := readMachineID()
+	if err != nil {
+		t.Fatalf("readMachineID() err: %v", err)
+	}
+	if want := hashMachineID([]byte("pod-uid")); !bytes.Equal(id, want[:]) {
+		t.Errorf("readMachineID() = %x, want %x", id, want[:])
+	}
+}
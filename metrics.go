@@ -0,0 +1,105 @@
+package xtoken
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MetricsHook receives generation events, its methods are called synchronously
+// from the generating goroutine and must be safe for concurrent use.
+type MetricsHook interface {
+	// Generated is called when n tokens are generated at once.
+	Generated(n int)
+	// CounterWrapped is called when the counter would wrap within one second,
+	// before New waits for the next one or NewStrict fails.
+	CounterWrapped()
+}
+
+// GeneratorStats are cumulative statistics of the process, shared by all
+// Generators.
+type GeneratorStats struct {
+	// Generated is the number of tokens generated.
+	Generated uint64
+	// LastCounter is the last value of the counter, it is random at startup.
+	LastCounter uint32
+	// CounterOverflows is the number of times the counter would have wrapped
+	// within one second.
+	CounterOverflows uint64
+}
+
+// generatedShardCount is the number of generatedShards, more than the Ps of
+// most machines.
+const generatedShardCount = 64
+
+// hookBox wraps a MetricsHook so it can be stored in an atomic.Pointer.
+type hookBox struct {
+	hook MetricsHook
+}
+
+var (
+	// metricsHook is the installed hook, nil when there is none.
+	metricsHook atomic.Pointer[hookBox]
+
+	// generatedShards count the generated tokens, each on its own cache line
+	// so that goroutines running on different Ps don't contend on one.
+	generatedShards [generatedShardCount]struct {
+		n atomic.Uint64
+		_ [56]byte
+	}
+
+	// generatedShard hands out the indexes of generatedShards, its per-P
+	// cache keeps the goroutines of a P on the same shard.
+	generatedShard = sync.Pool{
+		New: func() interface{} {
+			i := nextGeneratedShard.Add(1) % generatedShardCount
+			return &i
+		},
+	}
+
+	// nextGeneratedShard is the index of the last shard handed out.
+	nextGeneratedShard atomic.Uint32
+
+	// overflowCount is the number of counter overflows so far.
+	overflowCount atomic.Uint64
+)
+
+// SetMetricsHook installs h as the hook notified of generation events, nil
+// removes the current one. Without a hook, the events cost a nil check.
+func SetMetricsHook(h MetricsHook) {
+	if h == nil {
+		metricsHook.Store(nil)
+		return
+	}
+	metricsHook.Store(&hookBox{hook: h})
+}
+
+// Stats returns the generation statistics of the process.
+func Stats() GeneratorStats {
+	var n uint64
+	for i := range generatedShards {
+		n += generatedShards[i].n.Load()
+	}
+	return GeneratorStats{
+		Generated:        n,
+		LastCounter:      atomic.LoadUint32(&objectIDCounter) & maxCounter,
+		CounterOverflows: overflowCount.Load(),
+	}
+}
+
+// generated records that n tokens were generated.
+func generated(n int) {
+	i := generatedShard.Get().(*uint32)
+	generatedShards[*i].n.Add(uint64(n))
+	generatedShard.Put(i)
+	if b := metricsHook.Load(); b != nil {
+		b.hook.Generated(n)
+	}
+}
+
+// counterWrapped records that the counter would wrap within one second.
+func counterWrapped() {
+	overflowCount.Add(1)
+	if b := metricsHook.Load(); b != nil {
+		b.hook.CounterWrapped()
+	}
+}
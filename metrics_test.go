@@ -0,0 +1,128 @@
+package xtoken
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingHook struct {
+	generated, calls, wrapped atomic.Int64
+}
+
+func (h *recordingHook) Generated(n int) {
+	h.generated.Add(int64(n))
+	h.calls.Add(1)
+}
+
+func (h *recordingHook) CounterWrapped() { h.wrapped.Add(1) }
+
+// withHook installs h until the end of the test.
+func withHook(t *testing.T, h MetricsHook) {
+	t.Helper()
+	SetMetricsHook(h)
+	t.Cleanup(func() { SetMetricsHook(nil) })
+}
+
+func TestMetricsHook(t *testing.T) {
+	h := &recordingHook{}
+	withHook(t, h)
+	before := Stats()
+
+	const goroutines, perGoroutine, batch = 8, 1000, 10
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				New()
+			}
+			NewBatch(batch)
+			NewBatchStrings(batch)
+			if _, err := NewStrict(); err != nil {
+				t.Errorf("NewStrict() err: %v", err)
+			}
+			NewWithTime(time.Now())
+		}()
+	}
+	wg.Wait()
+
+	want := int64(goroutines * (perGoroutine + 2*batch + 2))
+	if got := h.generated.Load(); got != want {
+		t.Errorf("Generated() total = %d, want %d", got, want)
+	}
+	if got, want := h.calls.Load(), int64(goroutines*(perGoroutine+4)); got != want {
+		t.Errorf("Generated() calls = %d, want %d", got, want)
+	}
+	if got := h.wrapped.Load(); got != 0 {
+		t.Errorf("CounterWrapped() calls = %d, want 0", got)
+	}
+	after := Stats()
+	if got := int64(after.Generated - before.Generated); got != want {
+		t.Errorf("Stats().Generated delta = %d, want %d", got, want)
+	}
+	token := New()
+	if got, want := Stats().LastCounter, uint32(token.Counter()); got != want {
+		t.Errorf("Stats().LastCounter = %d, want %d", got, want)
+	}
+}
+
+func TestMetricsHookCounterWrapped(t *testing.T) {
+	h := &recordingHook{}
+	withHook(t, h)
+	secs := uint32(time.Now().Unix())
+	start := uint32(0x123456)
+	setCounter(defaultGenerator, secs, start, start+1<<24)
+	if _, err := defaultGenerator.newStrict(secs); err != ErrCounterOverflow {
+		t.Fatalf("newStrict() err = %v, want %v", err, ErrCounterOverflow)
+	}
+	if got := h.wrapped.Load(); got != 1 {
+		t.Errorf("CounterWrapped() calls = %d, want 1", got)
+	}
+	if got := h.generated.Load(); got != 0 {
+		t.Errorf("Generated() total = %d, want 0", got)
+	}
+}
+
+func TestSetMetricsHookNil(t *testing.T) {
+	h := &recordingHook{}
+	SetMetricsHook(h)
+	SetMetricsHook(nil)
+	before := Stats().Generated
+	New()
+	if got := h.generated.Load(); got != 0 {
+		t.Errorf("Generated() total = %d after removing the hook, want 0", got)
+	}
+	// Stats counts the tokens with or without a hook.
+	if got := Stats().Generated - before; got != 1 {
+		t.Errorf("Stats().Generated delta = %d without a hook, want 1", got)
+	}
+}
+
+func BenchmarkNewMetricsHook(b *testing.B) {
+	b.Run("nil", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = New()
+		}
+	})
+	b.Run("nil-parallel", func(b *testing.B) {
+		g := NewGenerator(WithShardedCounter())
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = g.New()
+			}
+		})
+	})
+	b.Run("hook", func(b *testing.B) {
+		SetMetricsHook(&recordingHook{})
+		defer SetMetricsHook(nil)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = New()
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package xtoken
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// Conversions with other identifiers, to ingest them during a migration. A
+// Token holds 8 bytes besides its timestamp, identifiers with more bits are
+// truncated or folded when converted to a Token, so the conversion is only
+// lossless for the identifiers made from a Token.
+
+const (
+	ulidEncodedLen  = 26 // ULID string encoded len
+	ksuidEncodedLen = 27 // KSUID string encoded len
+
+	// crockfordEncoding is the Crockford base32 alphabet of ULIDs.
+	crockfordEncoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// base62Encoding is the alphabet of KSUIDs.
+	base62Encoding = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// ksuidEpoch is the epoch of the KSUID timestamps, 2014-05-13T16:53:20Z.
+	ksuidEpoch = 1400000000
+
+	// ulidFold multiplies the bits FromULID drops before they are added to the
+	// kept ones, it's odd so that distinct dropped bits give distinct sums.
+	ulidFold = 0x9E3779B97F4A7C15
+)
+
+// crockfordDec and base62Dec are the decoding maps for crockfordEncoding, in
+// lower or upper case, and base62Encoding.
+var crockfordDec, base62Dec [256]byte
+
+func init() {
+	for i := 0; i < len(crockfordDec); i++ {
+		crockfordDec[i] = 0xFF
+		base62Dec[i] = 0xFF
+	}
+	for i := 0; i < len(crockfordEncoding); i++ {
+		crockfordDec[crockfordEncoding[i]] = byte(i)
+		crockfordDec[crockfordEncoding[i]|0x20] = byte(i)
+	}
+	for i := 0; i < len(base62Encoding); i++ {
+		base62Dec[base62Encoding[i]] = byte(i)
+	}
+}
+
+// ULID returns the token as a ULID: the timestamp in milliseconds, followed by
+// the 8 other bytes of the token and 2 zero bytes. FromULID reads it back.
+func (token Token) ULID() [16]byte {
+	var u [16]byte
+	ms := uint64(token.Unix()) * 1000
+	binary.BigEndian.PutUint16(u[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	copy(u[6:14], token[4:12])
+	return u
+}
+
+// ULIDString returns the 26 chars Crockford base32 representation of the ULID
+// of the token.
+func (token Token) ULIDString() string {
+	u := token.ULID()
+	hi, lo := binary.BigEndian.Uint64(u[0:8]), binary.BigEndian.Uint64(u[8:16])
+	var text [ulidEncodedLen]byte
+	for i := len(text) - 1; i >= 0; i-- {
+		text[i] = crockfordEncoding[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(text[:])
+}
+
+// FromULID converts a ULID into a Token: its timestamp truncated to the second,
+// followed by the first 8 bytes of its entropy. The milliseconds and the last
+// 2 bytes of the entropy don't fit, they are folded into the 8 bytes instead
+// of being dropped: ULIDs which only differ by them, such as those of a
+// monotonic ULID generator incrementing the entropy within a millisecond, give
+// distinct tokens. Other ULIDs may still collide, with a probability of about
+// 2^-64 per pair of the same second. The ULIDs of Token.ULID have nothing to
+// fold and are read back unchanged. Times which don't fit in the timestamp of a
+// Token return a *TimeOutOfRangeError.
+func FromULID(u [16]byte) (Token, error) {
+	ms := uint64(binary.BigEndian.Uint16(u[0:2]))<<32 | uint64(binary.BigEndian.Uint32(u[2:6]))
+	secs, err := checkedSeconds(time.UnixMilli(int64(ms)), 0)
+	if err != nil {
+		return nilToken, err
+	}
+	// The 26 dropped bits times an odd number never make a small multiple of
+	// 2^64, at least 2^37 away, so incrementing the entropy by less than 2^53
+	// always gives another token.
+	dropped := ms%1000<<16 | uint64(binary.BigEndian.Uint16(u[14:16]))
+	var token Token
+	binary.BigEndian.PutUint32(token[0:4], secs)
+	binary.BigEndian.PutUint64(token[4:12], binary.BigEndian.Uint64(u[6:14])+dropped*ulidFold)
+	return token, nil
+}
+
+// FromULIDString reads a Token from the 26 chars string representation of a
+// ULID, in lower or upper case, see FromULID.
+func FromULIDString(s string) (Token, error) {
+	if err := checkText(s, ulidEncodedLen, &crockfordDec); err != nil {
+		return nilToken, err
+	}
+	// 26 symbols hold 130 bits, the first one can't use its 2 high bits.
+	if crockfordDec[s[0]] > 7 {
+		return nilToken, ErrInconsistentToken
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(crockfordDec[s[i]])
+	}
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[0:8], hi)
+	binary.BigEndian.PutUint64(u[8:16], lo)
+	return FromULID(u)
+}
+
+// KSUID returns the token as a KSUID: the timestamp counted from the KSUID
+// epoch, followed by the 8 other bytes of the token and 8 zero bytes.
+// Tokens before the KSUID epoch return a *TimeOutOfRangeError.
+func (token Token) KSUID() ([20]byte, error) {
+	var k [20]byte
+	secs, err := checkedSeconds(token.Time(), ksuidEpoch)
+	if err != nil {
+		return k, err
+	}
+	binary.BigEndian.PutUint32(k[0:4], secs)
+	copy(k[4:12], token[4:12])
+	return k, nil
+}
+
+// KSUIDString returns the 27 chars base62 representation of the KSUID of the
+// token, see Token.KSUID.
+func (token Token) KSUIDString() (string, error) {
+	k, err := token.KSUID()
+	if err != nil {
+		return "", err
+	}
+	var text [ksuidEncodedLen]byte
+	for i := len(text) - 1; i >= 0; i-- {
+		// Divide the big endian number k by 62, the remainder is the next symbol.
+		var rem uint32
+		for j := range k {
+			v := rem<<8 | uint32(k[j])
+			k[j], rem = byte(v/62), v%62
+		}
+		text[i] = base62Encoding[rem]
+	}
+	return string(text[:]), nil
+}
+
+// FromKSUID converts a KSUID into a Token: its timestamp, followed by the
+// first 8 bytes of its payload. Times which don't fit in the timestamp of a
+// Token return a *TimeOutOfRangeError.
+func FromKSUID(k [20]byte) (Token, error) {
+	t := time.Unix(ksuidEpoch+int64(binary.BigEndian.Uint32(k[0:4])), 0)
+	secs, err := checkedSeconds(t, 0)
+	if err != nil {
+		return nilToken, err
+	}
+	var token Token
+	binary.BigEndian.PutUint32(token[0:4], secs)
+	copy(token[4:12], k[4:12])
+	return token, nil
+}
+
+// FromKSUIDString reads a Token from the 27 chars string representation of a
+// KSUID, see FromKSUID.
+func FromKSUIDString(s string) (Token, error) {
+	if err := checkText(s, ksuidEncodedLen, &base62Dec); err != nil {
+		return nilToken, err
+	}
+	var k [20]byte
+	for i := 0; i < len(s); i++ {
+		// Multiply the big endian number k by 62 and add the symbol.
+		carry := uint32(base62Dec[s[i]])
+		for j := len(k) - 1; j >= 0; j-- {
+			v := uint32(k[j])*62 + carry
+			k[j], carry = byte(v), v>>8
+		}
+		if carry != 0 {
+			return nilToken, ErrInconsistentToken
+		}
+	}
+	return FromKSUID(k)
+}
@@ -0,0 +1,144 @@
+package xtoken
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromULIDString(t *testing.T) {
+	// Example of the ULID specification.
+	const s = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	for _, in := range []string{s, strings.ToLower(s)} {
+		token, err := FromULIDString(in)
+		if err != nil {
+			t.Fatalf("FromULIDString(%q) err: %v", in, err)
+		}
+		if got, want := token.Unix(), int64(1469922850); got != want {
+			t.Errorf("Unix() = %d, want %d", got, want)
+		}
+		// The first 8 bytes of the entropy are d6764c61efb99302, the 259ms and
+		// the last 2 bytes are folded into them.
+		if got, want := hex.EncodeToString(token[4:]), "2fb84ca7b60e2f79"; got != want {
+			t.Errorf("FromULIDString(%q) bytes = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestULID(t *testing.T) {
+	for _, v := range IDs {
+		s := v.token.ULIDString()
+		if len(s) != ulidEncodedLen {
+			t.Fatalf("len(ULIDString()) = %d, want %d", len(s), ulidEncodedLen)
+		}
+		got, err := FromULIDString(s)
+		if err != nil || got != v.token {
+			t.Errorf("FromULIDString(%q) = %v, %v, want %v", s, got, err, v.token)
+		}
+	}
+	u := IDs[0].token.ULID()
+	if got := time.UnixMilli(int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])); !got.Equal(IDs[0].token.Time()) {
+		t.Errorf("ULID() time = %v, want %v", got, IDs[0].token.Time())
+	}
+}
+
+func TestFromULIDMonotonic(t *testing.T) {
+	// A monotonic ULID generator increments the 80-bit entropy within a
+	// millisecond, the increments carry into the first 8 bytes.
+	var u [16]byte
+	ms := uint64(1700000000123)
+	binary.BigEndian.PutUint16(u[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	copy(u[6:16], "\x5e\x11\x02\x9a\xff\xff\xff\xff\xff\x00")
+	seen := make(map[Token]int)
+	for i := 0; i < 200000; i++ {
+		token, err := FromULID(u)
+		if err != nil {
+			t.Fatalf("FromULID() err: %v", err)
+		}
+		if j, ok := seen[token]; ok {
+			t.Fatalf("FromULID() of increments %d and %d = %v", j, i, token)
+		}
+		seen[token] = i
+		for k := 15; k >= 6; k-- {
+			if u[k]++; u[k] != 0 {
+				break
+			}
+		}
+	}
+
+	// Only the milliseconds differ.
+	x, _ := FromULID(u)
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms+1))
+	if y, _ := FromULID(u); x == y {
+		t.Errorf("FromULID() of ULIDs 1ms apart = %v", x)
+	}
+}
+
+func TestFromULIDStringInvalid(t *testing.T) {
+	for _, s := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FA!", "81ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if _, err := FromULIDString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromULIDString(%q) err = %v, want ErrInvalidToken", s, err)
+		}
+	}
+	// Far future ULIDs don't fit in a Token.
+	if _, err := FromULIDString("7ZZZZZZZZZZZZZZZZZZZZZZZZZ"); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("FromULIDString() err = %v, want ErrTimeOutOfRange", err)
+	}
+}
+
+func TestFromKSUIDString(t *testing.T) {
+	// Example of github.com/segmentio/ksuid.
+	token, err := FromKSUIDString("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatalf("FromKSUIDString() err: %v", err)
+	}
+	if got, want := token.Unix(), int64(ksuidEpoch+107608047); got != want {
+		t.Errorf("Unix() = %d, want %d", got, want)
+	}
+	if got, want := hex.EncodeToString(token[4:]), "b5a1cd34b5f99d11"; got != want {
+		t.Errorf("FromKSUIDString() bytes = %s, want %s", got, want)
+	}
+}
+
+func TestKSUID(t *testing.T) {
+	token := xidVectors[4].token
+	s, err := token.KSUIDString()
+	if err != nil {
+		t.Fatalf("KSUIDString() err: %v", err)
+	}
+	if len(s) != ksuidEncodedLen {
+		t.Fatalf("len(KSUIDString()) = %d, want %d", len(s), ksuidEncodedLen)
+	}
+	if got, err := FromKSUIDString(s); err != nil || got != token {
+		t.Errorf("FromKSUIDString(%q) = %v, %v, want %v", s, got, err, token)
+	}
+	k, _ := token.KSUID()
+	if !bytes.Equal(k[4:12], token[4:]) || !bytes.Equal(k[12:], make([]byte, 8)) {
+		t.Errorf("KSUID() = %x, want the token bytes and zero padding", k)
+	}
+	// Tokens before the KSUID epoch don't fit.
+	if _, err := IDs[1].token.KSUIDString(); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("KSUIDString() err = %v, want ErrTimeOutOfRange", err)
+	}
+}
+
+func TestFromKSUIDStringInvalid(t *testing.T) {
+	for _, s := range []string{"", "0ujtsYcgvSTl8PAuAdqWYSMnLO", "0ujtsYcgvSTl8PAuAdqWYSMnLO-", "zzzzzzzzzzzzzzzzzzzzzzzzzzz"} {
+		if _, err := FromKSUIDString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromKSUIDString(%q) err = %v, want ErrInvalidToken", s, err)
+		}
+	}
+}
+
+func TestXID(t *testing.T) {
+	for _, v := range xidVectors {
+		if got := FromXID(v.token.XID()); got != v.token {
+			t.Errorf("FromXID(XID()) = %v, want %v", got, v.token)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package xtoken
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	milliEncodedLen = 23 // TokenMilli string encoded len
+	milliRawLen     = 14 // TokenMilli binary raw len
+)
+
+// TokenMilli is a variant of Token with a 6-byte millisecond timestamp:
+//   - 6-byte value representing the milliseconds since the Unix epoch,
+//   - 3-byte machine identifier,
+//   - 2-byte process id, and
+//   - 3-byte counter, shared with Token.
+//
+// Its string form uses fixed positions, so both the raw bytes and the encoded
+// strings sort in creation order with a resolution of one millisecond.
+type TokenMilli [milliRawLen]byte
+
+var nilTokenMilli TokenMilli
+
+// NewMilli generates a globally unique TokenMilli
+func NewMilli() TokenMilli {
+	return NewMilliWithTime(time.Now())
+}
+
+// NewMilliWithTime generates a globally unique TokenMilli with the passed in time
+func NewMilliWithTime(t time.Time) TokenMilli {
+	var token TokenMilli
+	// Timestamp, 6 bytes, big endian
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	token[0] = byte(ms >> 40)
+	token[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(token[2:], uint32(ms))
+	// Machine ID, 3 bytes
+	m := machineID.Load()
+	token[6] = m[0]
+	token[7] = m[1]
+	token[8] = m[2]
+	// Pid, 2 bytes, big endian
+	p := pid.Load()
+	token[9] = byte(p >> 8)
+	token[10] = byte(p)
+	// Increment, 3 bytes, big endian
+	i := atomic.AddUint32(&objectIDCounter, 1)
+	token[11] = byte(i >> 16)
+	token[12] = byte(i >> 8)
+	token[13] = byte(i)
+	return token
+}
+
+// UnixMilli returns the timestamp part of the token as milliseconds since the Unix epoch.
+func (token TokenMilli) UnixMilli() int64 {
+	return int64(token[0])<<40 | int64(token[1])<<32 | int64(binary.BigEndian.Uint32(token[2:6]))
+}
+
+// Time returns the timestamp part of the token with millisecond resolution.
+func (token TokenMilli) Time() time.Time {
+	ms := token.UnixMilli()
+	return time.Unix(ms/1e3, (ms%1e3)*int64(time.Millisecond))
+}
+
+// Machine returns the 3-byte machine id part of the token.
+func (token TokenMilli) Machine() []byte {
+	return token[6:9]
+}
+
+// Pid returns the process id part of the token.
+func (token TokenMilli) Pid() uint16 {
+	return binary.BigEndian.Uint16(token[9:11])
+}
+
+// Counter returns the incrementing value part of the token.
+func (token TokenMilli) Counter() int32 {
+	b := token[11:14]
+	return int32(uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]))
+}
+
+// FromStringMilli reads a TokenMilli from its string representation
+func FromStringMilli(s string) (TokenMilli, error) {
+	var token TokenMilli
+	if err := checkText(s, milliEncodedLen, &sortableDec); err != nil {
+		return token, err
+	}
+	if !decodeBase32(token[:], s, &sortableDec) {
+		return nilTokenMilli, ErrInconsistentToken
+	}
+	return token, nil
+}
+
+// String returns a 23 chars base32hex lowercased representation of the token (char set is 0-9, a-v).
+func (token TokenMilli) String() string {
+	text := make([]byte, milliEncodedLen)
+	encodeBase32(text, token[:], sortableEncoding)
+	return string(text)
+}
+
+// IsZero Returns true if this is a "nil" TokenMilli
+func (token TokenMilli) IsZero() bool {
+	return token == nilTokenMilli
+}
+
+// Bytes returns the byte array representation of the token
+func (token TokenMilli) Bytes() []byte {
+	return token[:]
+}
+
+// Compare returns an integer comparing two tokens. It behaves just like `bytes.Compare`.
+func (token TokenMilli) Compare(other TokenMilli) int {
+	return bytes.Compare(token[:], other[:])
+}
@@ -0,0 +1,73 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewMilliWithTime(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	token := NewMilliWithTime(now)
+	if got, want := token.Time(), now.Truncate(time.Millisecond); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v", got, want)
+	}
+	if got, want := token.UnixMilli(), int64(1700000000123); got != want {
+		t.Errorf("UnixMilli() = %d, want %d", got, want)
+	}
+	if m := machineID.Load(); !bytes.Equal(token.Machine(), m[:3]) {
+		t.Errorf("Machine() = %x, want %x", token.Machine(), m[:3])
+	}
+	if got, want := token.Pid(), CurrentPid(); got != want {
+		t.Errorf("Pid() = %d, want %d", got, want)
+	}
+
+	s := token.String()
+	if len(s) != milliEncodedLen {
+		t.Errorf("len(String()) = %d, want %d", len(s), milliEncodedLen)
+	}
+	got, err := FromStringMilli(s)
+	if err != nil {
+		t.Fatalf("FromStringMilli(%q) err: %v", s, err)
+	}
+	if got != token {
+		t.Errorf("FromStringMilli(%q) = %x, want %x", s, got[:], token[:])
+	}
+}
+
+func TestTokenMilliOrder(t *testing.T) {
+	now := time.Now()
+	// The later token is generated first, so the counter alone would order them wrongly.
+	later := NewMilliWithTime(now.Add(5 * time.Millisecond))
+	earlier := NewMilliWithTime(now)
+	if earlier.Compare(later) != -1 {
+		t.Errorf("earlier.Compare(later) = %d, want -1", earlier.Compare(later))
+	}
+	if strings.Compare(earlier.String(), later.String()) != -1 {
+		t.Errorf("strings.Compare(%q, %q) != -1", earlier.String(), later.String())
+	}
+	if got := later.Time().Sub(earlier.Time()); got != 5*time.Millisecond {
+		t.Errorf("Time() delta = %v, want 5ms", got)
+	}
+}
+
+func TestFromStringMilliInvalid(t *testing.T) {
+	valid := NewMilli().String()
+	for _, s := range []string{
+		"",
+		valid[1:],
+		valid + "0",
+		"W" + valid[1:],
+		valid[:milliEncodedLen-1] + "1", // padding bits set
+	} {
+		if _, err := FromStringMilli(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromStringMilli(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+	var zero TokenMilli
+	if !zero.IsZero() {
+		t.Error("IsZero() = false, want true")
+	}
+}
@@ -0,0 +1,56 @@
+package xtoken
+
+// MessagePack formats, see https://github.com/msgpack/msgpack/blob/master/spec.md.
+const (
+	msgpackNil    = 0xc0
+	msgpackBin8   = 0xc4
+	msgpackFixStr = 0xa0 // | length, up to 31
+	msgpackStr8   = 0xd9
+)
+
+// MarshalMsgpack implements msgpack.Marshaler of github.com/vmihailenco/msgpack,
+// the token is serialized as a 12 bytes binary, the nil token as well.
+func (token Token) MarshalMsgpack() ([]byte, error) {
+	b := make([]byte, 2+rawLen)
+	b[0], b[1] = msgpackBin8, rawLen
+	copy(b[2:], token[:])
+	return b, nil
+}
+
+// UnmarshalMsgpack implements msgpack.Unmarshaler of
+// github.com/vmihailenco/msgpack. It accepts a 12 bytes binary, a string read
+// by ParseAny, and nil as the nil token.
+func (token *Token) UnmarshalMsgpack(b []byte) error {
+	if len(b) == 0 {
+		return ErrInvalidToken
+	}
+	var data []byte
+	switch {
+	case b[0] == msgpackNil && len(b) == 1:
+		*token = nilToken
+		return nil
+	case b[0] == msgpackBin8 && len(b) >= 2:
+		if int(b[1]) != len(b)-2 {
+			return ErrInvalidToken
+		}
+		return token.UnmarshalBinary(b[2:])
+	case b[0]&0xe0 == msgpackFixStr:
+		data = b[1:]
+		if int(b[0]&0x1f) != len(data) {
+			return ErrInvalidToken
+		}
+	case b[0] == msgpackStr8 && len(b) >= 2:
+		data = b[2:]
+		if int(b[1]) != len(data) {
+			return ErrInvalidToken
+		}
+	default:
+		return ErrInvalidToken
+	}
+	t, err := ParseAny(string(data))
+	if err != nil {
+		return err
+	}
+	*token = t
+	return nil
+}
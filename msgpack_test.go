@@ -0,0 +1,56 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestMsgpack(t *testing.T) {
+	for _, v := range IDs {
+		b, err := v.token.MarshalMsgpack()
+		if err != nil {
+			t.Fatalf("MarshalMsgpack() err: %v", err)
+		}
+		if want := append([]byte{0xc4, 12}, v.token[:]...); !bytes.Equal(b, want) {
+			t.Errorf("MarshalMsgpack() = %x, want %x", b, want)
+		}
+		var got Token
+		if err := got.UnmarshalMsgpack(b); err != nil || got != v.token {
+			t.Errorf("UnmarshalMsgpack(%x) = %x, %v, want %x", b, got[:], err, v.token[:])
+		}
+	}
+}
+
+func TestUnmarshalMsgpack(t *testing.T) {
+	token := IDs[0].token
+	s, hex := token.String(), token.Hex()
+	tests := []struct {
+		name string
+		in   []byte
+		want Token
+		err  error
+	}{
+		{"nil", []byte{0xc0}, nilToken, nil},
+		{"str8 string", append([]byte{0xd9, 32}, s...), token, nil},
+		{"fixstr hex", append([]byte{0xa0 | 24}, hex...), token, nil},
+		{"empty", nil, nilToken, ErrInvalidToken},
+		{"short bin", append([]byte{0xc4, 11}, token[:11]...), nilToken, ErrBadLength},
+		{"bin length mismatch", append([]byte{0xc4, 13}, token[:]...), nilToken, ErrInvalidToken},
+		{"str8 length mismatch", append([]byte{0xd9, 31}, s...), nilToken, ErrInvalidToken},
+		{"invalid string", append([]byte{0xd9, 32}, bytes.Repeat([]byte{'!'}, 32)...), nilToken, ErrInvalidToken},
+		{"integer", []byte{0x01}, nilToken, ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IDs[1].token
+			err := got.UnmarshalMsgpack(tt.in)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("UnmarshalMsgpack() err = %v, want %v", err, tt.err)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("UnmarshalMsgpack() = %x, want %x", got[:], tt.want[:])
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package xtoken
+
+import "encoding/binary"
+
+const (
+	// BSON element types read by UnmarshalBSONValue, see https://bsonspec.org/spec.html.
+	bsonTypeString    = 0x02
+	bsonTypeBinary    = 0x05
+	bsonTypeUndefined = 0x06
+	bsonTypeObjectID  = 0x07
+	bsonTypeNull      = 0x0A
+)
+
+// ObjectIDHex returns the 24 chars lowercase hex representation of the token,
+// the format of MongoDB ObjectId.Hex. It's the same as Hex.
+func (token Token) ObjectIDHex() string {
+	return token.Hex()
+}
+
+// FromObjectIDHex reads a Token from the 24 chars hex representation of a
+// MongoDB ObjectId, it's the same as FromHex.
+func FromObjectIDHex(s string) (Token, error) {
+	return FromHex(s)
+}
+
+// MarshalBSONValue implements bson.ValueMarshaler of the mongo-go-driver v2,
+// the token is stored as a native ObjectId. The bsoncompat package adapts it
+// to the v1 driver.
+func (token Token) MarshalBSONValue() (byte, []byte, error) {
+	data := make([]byte, rawLen)
+	copy(data, token[:])
+	return bsonTypeObjectID, data, nil
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler of the mongo-go-driver v2,
+// it accepts an ObjectId, a 12 bytes binary of any subtype, a string read by
+// ParseAny, and null or undefined as the nil token.
+func (token *Token) UnmarshalBSONValue(typ byte, data []byte) error {
+	switch typ {
+	case bsonTypeNull, bsonTypeUndefined:
+		*token = nilToken
+		return nil
+	case bsonTypeObjectID:
+		return token.UnmarshalBinary(data)
+	case bsonTypeBinary:
+		// int32 length, subtype, bytes
+		if len(data) < 5 || int(binary.LittleEndian.Uint32(data)) != len(data)-5 {
+			return ErrInvalidToken
+		}
+		return token.UnmarshalBinary(data[5:])
+	case bsonTypeString:
+		// int32 length including the trailing NUL, chars, NUL
+		if len(data) < 5 || int(binary.LittleEndian.Uint32(data)) != len(data)-4 || data[len(data)-1] != 0 {
+			return ErrInvalidToken
+		}
+		t, err := ParseAny(string(data[4 : len(data)-1]))
+		if err != nil {
+			return err
+		}
+		*token = t
+		return nil
+	}
+	return ErrInvalidToken
+}
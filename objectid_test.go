@@ -0,0 +1,118 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestObjectIDHex(t *testing.T) {
+	for _, v := range IDs {
+		s := v.token.ObjectIDHex()
+		if len(s) != hexEncodedLen {
+			t.Errorf("len(ObjectIDHex()) = %d, want %d", len(s), hexEncodedLen)
+		}
+		got, err := FromObjectIDHex(s)
+		if err != nil {
+			t.Fatalf("FromObjectIDHex(%q) err: %v", s, err)
+		}
+		if got != v.token {
+			t.Errorf("FromObjectIDHex(%q) = %x, want %x", s, got[:], v.token[:])
+		}
+	}
+	if got, want := IDs[0].token.ObjectIDHex(), "4d88e15b60f486e428412dc9"; got != want {
+		t.Errorf("ObjectIDHex() = %q, want %q", got, want)
+	}
+	if got, err := FromObjectIDHex("4D88E15B60F486E428412DC9"); err != nil || got != IDs[0].token {
+		t.Errorf("FromObjectIDHex() uppercase = %x, %v, want %x", got[:], err, IDs[0].token[:])
+	}
+}
+
+func TestFromObjectIDHexInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"4d88e15b60f486e428412dc",   // odd length
+		"4d88e15b60f486e428412dc9a", // odd length
+		"4d88e15b60f486e428412dc9aa",
+		"4d88e15b60f486e428412dcz",
+		"4d88e15b60f486e428412d c",
+	} {
+		if _, err := FromObjectIDHex(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromObjectIDHex(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestBSONValue(t *testing.T) {
+	for _, v := range IDs {
+		typ, data, err := v.token.MarshalBSONValue()
+		if err != nil {
+			t.Fatalf("MarshalBSONValue() err: %v", err)
+		}
+		if typ != bsonTypeObjectID {
+			t.Errorf("MarshalBSONValue() type = %#x, want %#x", typ, bsonTypeObjectID)
+		}
+		if !bytes.Equal(data, v.token[:]) {
+			t.Errorf("MarshalBSONValue() data = %x, want %x", data, v.token[:])
+		}
+		var got Token
+		if err := got.UnmarshalBSONValue(typ, data); err != nil {
+			t.Fatalf("UnmarshalBSONValue() err: %v", err)
+		}
+		if got != v.token {
+			t.Errorf("UnmarshalBSONValue() = %x, want %x", got[:], v.token[:])
+		}
+	}
+
+	got := New()
+	if err := got.UnmarshalBSONValue(bsonTypeNull, nil); err != nil || !got.IsZero() {
+		t.Errorf("UnmarshalBSONValue(null) = %v, %v, want nil token", got, err)
+	}
+	if err := got.UnmarshalBSONValue(bsonTypeObjectID, []byte{1, 2, 3}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("UnmarshalBSONValue() short data err = %v, want %v", err, ErrInvalidToken)
+	}
+	if err := got.UnmarshalBSONValue(0x10, []byte{1, 2, 3, 4}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("UnmarshalBSONValue() int32 err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestUnmarshalBSONValueBinaryString(t *testing.T) {
+	token := New()
+	bin := append([]byte{rawLen, 0, 0, 0, 0x80}, token[:]...)
+	str := append(append([]byte{encodedLen + 1, 0, 0, 0}, token.String()...), 0)
+	hex := append(append([]byte{hexEncodedLen + 1, 0, 0, 0}, token.Hex()...), 0)
+	tests := []struct {
+		name string
+		typ  byte
+		data []byte
+	}{
+		{"binary", bsonTypeBinary, bin},
+		{"string", bsonTypeString, str},
+		{"hex string", bsonTypeString, hex},
+	}
+	for _, tt := range tests {
+		var got Token
+		if err := got.UnmarshalBSONValue(tt.typ, tt.data); err != nil || got != token {
+			t.Errorf("%s: UnmarshalBSONValue() = %v, %v, want %v", tt.name, got, err, token)
+		}
+	}
+
+	got := token
+	if err := got.UnmarshalBSONValue(bsonTypeUndefined, nil); err != nil || !got.IsZero() {
+		t.Errorf("UnmarshalBSONValue(undefined) = %v, %v, want nil token", got, err)
+	}
+	for _, tt := range []struct {
+		name string
+		typ  byte
+		data []byte
+	}{
+		{"short binary", bsonTypeBinary, bin[:10]},
+		{"binary length", bsonTypeBinary, append([]byte{rawLen + 1}, bin[1:]...)},
+		{"string without NUL", bsonTypeString, str[:len(str)-1]},
+		{"invalid string", bsonTypeString, []byte{2, 0, 0, 0, 'x', 0}},
+	} {
+		if err := got.UnmarshalBSONValue(tt.typ, tt.data); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: UnmarshalBSONValue() err = %v, want %v", tt.name, err, ErrInvalidToken)
+		}
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"strings"
 )
 
+// readPlatformMachineID reads the IOPlatformUUID of the hardware from the
+// output of ioreg. The kern.uuid sysctl isn't a substitute: it's the UUID of
+// the kernel image, shared by every machine running the same build.
 func readPlatformMachineID() (string, error) {
 	ioreg, err := exec.LookPath("ioreg")
 	if err != nil {
@@ -0,0 +1,24 @@
+//go:build darwin
+// +build darwin
+
+package xtoken
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestReadPlatformMachineIDDarwin(t *testing.T) {
+	id, err := readPlatformMachineID()
+	if err != nil {
+		t.Fatalf("readPlatformMachineID() err: %v", err)
+	}
+	if len(id) != 36 {
+		t.Errorf("readPlatformMachineID() = %q, want a 36 chars UUID", id)
+	}
+	// kern.uuid identifies the kernel build, not the hardware.
+	if kern, err := syscall.Sysctl("kern.uuid"); err == nil && strings.EqualFold(kern, id) {
+		t.Errorf("readPlatformMachineID() = %q, the kern.uuid of the kernel image", id)
+	}
+}
@@ -5,12 +5,31 @@ package xtoken
 
 import (
 	"os"
+	"strings"
 )
 
+// machineIDFiles are read in order by readPlatformMachineID, the first one
+// holding an initialized id wins. The D-Bus copy is often the only one in
+// containers, product_uuid identifies the hardware and requires root.
+var machineIDFiles = []string{
+	"/etc/machine-id",
+	"/var/lib/dbus/machine-id",
+	"/sys/class/dmi/id/product_uuid",
+}
+
 func readPlatformMachineID() (string, error) {
-	b, err := os.ReadFile("/etc/machine-id")
-	if err != nil || len(b) == 0 {
-		b, err = os.ReadFile("/sys/class/dmi/id/product_uuid")
+	err := error(os.ErrNotExist)
+	for _, name := range machineIDFiles {
+		var b []byte
+		b, err = os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		// systemd writes "uninitialized" until the first boot completes.
+		if id := strings.TrimSpace(string(b)); id != "" && id != "uninitialized" {
+			return id, nil
+		}
+		err = os.ErrNotExist
 	}
-	return string(b), err
+	return "", err
 }
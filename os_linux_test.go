@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+package xtoken
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadPlatformMachineIDLinux(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	empty := write("empty", "\n")
+	uninitialized := write("uninitialized", "uninitialized\n")
+	dbus := write("dbus", "0123456789abcdef0123456789abcdef\n")
+	missing := filepath.Join(dir, "missing")
+
+	saved := machineIDFiles
+	t.Cleanup(func() { machineIDFiles = saved })
+
+	machineIDFiles = []string{missing, empty, uninitialized, dbus}
+	id, err := readPlatformMachineID()
+	if err != nil || id != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("readPlatformMachineID() = %q, %v, want the D-Bus machine id", id, err)
+	}
+
+	machineIDFiles = []string{missing, empty}
+	if id, err := readPlatformMachineID(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("readPlatformMachineID() = %q, %v, want os.ErrNotExist", id, err)
+	}
+}
@@ -3,8 +3,10 @@
 
 package xtoken
 
-import "errors"
+import "os"
 
+// readPlatformMachineID has no platform source on this GOOS (js/wasm, plan9,
+// aix, ...), so readMachineID falls back to the hostname or random bytes.
 func readPlatformMachineID() (string, error) {
-	return "", errors.New("not implemented")
+	return "", os.ErrNotExist
 }
@@ -0,0 +1,53 @@
+package xtoken
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestCrossCompile makes sure every supported GOOS has a readPlatformMachineID,
+// either a native one or the os_other.go fallback.
+func TestCrossCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping cross compilation in short mode")
+	}
+	goBin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goBin); err != nil {
+		t.Skipf("go tool not available: %v", err)
+	}
+	targets := []struct{ goos, goarch string }{
+		{"linux", "amd64"},
+		{"darwin", "arm64"},
+		{"windows", "amd64"},
+		{"freebsd", "amd64"},
+		{"openbsd", "amd64"},
+		{"netbsd", "amd64"},
+		{"plan9", "amd64"},
+		{"aix", "ppc64"},
+		{"js", "wasm"},
+		{"wasip1", "wasm"},
+	}
+	for _, target := range targets {
+		target := target
+		t.Run(target.goos+"/"+target.goarch, func(t *testing.T) {
+			cmd := exec.Command(goBin, "vet", ".")
+			cmd.Env = append(os.Environ(), "GOOS="+target.goos, "GOARCH="+target.goarch, "CGO_ENABLED=0")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go vet failed: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestReadMachineID(t *testing.T) {
+	id, err := readMachineID()
+	if err != nil {
+		t.Fatalf("readMachineID() err: %v", err)
+	}
+	if len(id) != 4 {
+		t.Fatalf("len(readMachineID()) = %d, want 4", len(id))
+	}
+}
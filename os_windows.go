@@ -38,13 +38,16 @@ func readPlatformMachineID() (string, error) {
 
 	err = syscall.RegQueryValueEx(h, mGuidPtr, nil, &valType, (*byte)(unsafe.Pointer(&regBuf[0])), &bufLen)
 	if err != nil {
-		return "", fmt.Errorf("error parsing ")
+		return "", fmt.Errorf("error reading machine GUID: %w", err)
+	}
+	if valType != syscall.REG_SZ {
+		return "", fmt.Errorf("machine GUID has registry type %d, want REG_SZ", valType)
 	}
 
-	hostID := syscall.UTF16ToString(regBuf[:])
+	hostID := syscall.UTF16ToString(regBuf[:bufLen/2])
 	hostIDLen := len(hostID)
 	if hostIDLen != uuidLen {
-		return "", fmt.Errorf("HostID incorrect: %q\n", hostID)
+		return "", fmt.Errorf("machine GUID incorrect: %q", hostID)
 	}
 
 	return hostID, nil
@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package xtoken
+
+import "testing"
+
+func TestReadPlatformMachineIDWindows(t *testing.T) {
+	id, err := readPlatformMachineID()
+	if err != nil {
+		t.Fatalf("readPlatformMachineID() err: %v", err)
+	}
+	if len(id) != 36 {
+		t.Errorf("readPlatformMachineID() = %q, want a 36 chars GUID", id)
+	}
+}
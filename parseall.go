@@ -0,0 +1,78 @@
+package xtoken
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError is the error of one element of the input of ParseAll.
+type ParseError struct {
+	Index int   // index of the element in the input
+	Err   error // error FromString returned for it
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("index %d: %v", e.Index, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// MultiParseError is returned by ParseAll when some elements can't be parsed.
+// errors.Is and errors.As look through its errors.
+type MultiParseError struct {
+	Errors []*ParseError // errors of the failing elements, by increasing index
+}
+
+func (e *MultiParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d invalid Tokens: ", len(e.Errors))
+	for i, err := range e.Errors {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e *MultiParseError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ParseAll reads the tokens of ss like FromString. It parses every element,
+// the ones that fail are left as the nil token and reported by a
+// *MultiParseError along with their index.
+func ParseAll(ss []string) ([]Token, error) {
+	tokens := make([]Token, len(ss))
+	var errs []*ParseError
+	for i, s := range ss {
+		token, err := FromString(s)
+		if err != nil {
+			errs = append(errs, &ParseError{Index: i, Err: err})
+			continue
+		}
+		tokens[i] = token
+	}
+	if errs != nil {
+		return tokens, &MultiParseError{Errors: errs}
+	}
+	return tokens, nil
+}
+
+// ParseAllStrict is like ParseAll but stops at the first element that can't
+// be parsed, it returns nil and a *ParseError.
+func ParseAllStrict(ss []string) ([]Token, error) {
+	tokens := make([]Token, len(ss))
+	for i, s := range ss {
+		token, err := FromString(s)
+		if err != nil {
+			return nil, &ParseError{Index: i, Err: err}
+		}
+		tokens[i] = token
+	}
+	return tokens, nil
+}
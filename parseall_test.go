@@ -0,0 +1,116 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	a, b := New(), New()
+	tests := []struct {
+		name    string
+		in      []string
+		want    []Token
+		invalid []int
+	}{
+		{"empty", nil, []Token{}, nil},
+		{"all valid", []string{a.String(), b.String()}, []Token{a, b}, nil},
+		{"all invalid", []string{"", "bad"}, []Token{nilToken, nilToken}, []int{0, 1}},
+		{"mixed", []string{a.String(), "bad", b.String(), "________________________________"}, []Token{a, nilToken, b, nilToken}, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAll(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseAll() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseAll()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if tt.invalid == nil {
+				if err != nil {
+					t.Fatalf("ParseAll() err: %v", err)
+				}
+				return
+			}
+			var multiErr *MultiParseError
+			if !errors.As(err, &multiErr) {
+				t.Fatalf("ParseAll() err = %v, want a *MultiParseError", err)
+			}
+			if len(multiErr.Errors) != len(tt.invalid) {
+				t.Fatalf("MultiParseError.Errors = %v, want indexes %v", multiErr.Errors, tt.invalid)
+			}
+			for i, e := range multiErr.Errors {
+				if e.Index != tt.invalid[i] {
+					t.Errorf("Errors[%d].Index = %d, want %d", i, e.Index, tt.invalid[i])
+				}
+				if _, want := FromString(tt.in[e.Index]); e.Err.Error() != want.Error() {
+					t.Errorf("Errors[%d].Err = %v, want %v", i, e.Err, want)
+				}
+			}
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("errors.Is(%v, ErrInvalidToken) = false, want true", err)
+			}
+		})
+	}
+}
+
+func TestParseAllErrorMessage(t *testing.T) {
+	_, err := ParseAll([]string{New().String(), "bad", "________________________________"})
+	want := "2 invalid Tokens: index 1: invalid Token: length 3, want 32; index 2: invalid Token: inconsistent encoding"
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseAll() err = %v, want %q", err, want)
+	}
+	var lengthErr *InvalidLengthError
+	if !errors.As(err, &lengthErr) || lengthErr.Got != 3 {
+		t.Errorf("errors.As(%v, *InvalidLengthError) failed", err)
+	}
+}
+
+func TestParseAllStrict(t *testing.T) {
+	a, b := New(), New()
+	got, err := ParseAllStrict([]string{a.String(), b.String()})
+	if err != nil || len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("ParseAllStrict() = %v, %v, want [%v %v]", got, err, a, b)
+	}
+	got, err = ParseAllStrict([]string{a.String(), "bad", "worse"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 1 || got != nil {
+		t.Errorf("ParseAllStrict() = %v, %v, want nil and a *ParseError at 1", got, err)
+	}
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("errors.Is(%v, ErrInvalidToken) = false, want true", err)
+	}
+}
+
+func benchmarkInput() []string {
+	return NewBatchStrings(10000)
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	ss := benchmarkInput()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseAll(ss)
+	}
+}
+
+// BenchmarkParseLoop is the naive loop, to compare with BenchmarkParseAll.
+func BenchmarkParseLoop(b *testing.B) {
+	ss := benchmarkInput()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tokens := make([]Token, 0, len(ss))
+		var errs []error
+		for _, s := range ss {
+			token, err := FromString(s)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			tokens = append(tokens, token)
+		}
+		_ = errors.Join(errs...)
+	}
+}
@@ -0,0 +1,44 @@
+package xtoken
+
+import (
+	"fmt"
+)
+
+// ParseAny reads a Token from v, which must be a string, a []byte, a [12]byte
+// or a Token. Strings and byte slices are dispatched on their length:
+//   - 12 bytes are the raw token, this only applies to []byte,
+//   - 24 chars are hex, even when they are also chars of the String alphabet,
+//   - 32 chars are the String encoding.
+//
+// Anything else is rejected with an error wrapping ErrInvalidToken.
+func ParseAny(v interface{}) (Token, error) {
+	switch v := v.(type) {
+	case Token:
+		return v, nil
+	case [rawLen]byte:
+		return Token(v), nil
+	case []byte:
+		if len(v) == rawLen {
+			var token Token
+			copy(token[:], v)
+			return token, nil
+		}
+		return parseAnyString(string(v))
+	case string:
+		return parseAnyString(v)
+	case nil:
+		return nilToken, fmt.Errorf("%w: nil value", ErrInvalidToken)
+	}
+	return nilToken, fmt.Errorf("%w: unsupported type %T", ErrInvalidToken, v)
+}
+
+func parseAnyString(s string) (Token, error) {
+	switch len(s) {
+	case hexEncodedLen:
+		return FromHex(s)
+	case encodedLen:
+		return FromString(s)
+	}
+	return nilToken, fmt.Errorf("%w: got %d chars, want %d raw bytes, %d hex chars or %d encoded chars",
+		ErrInvalidToken, len(s), rawLen, hexEncodedLen, encodedLen)
+}
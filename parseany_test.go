@@ -0,0 +1,54 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAny(t *testing.T) {
+	token := IDs[0].token
+	s := token.String()
+	tests := []struct {
+		name string
+		v    interface{}
+		want Token
+		err  bool
+	}{
+		{"Token", token, token, false},
+		{"[12]byte", [12]byte(token), token, false},
+		{"raw []byte", token.Bytes(), token, false},
+		{"printable raw []byte", []byte("abcdefabcdef"), Token{'a', 'b', 'c', 'd', 'e', 'f', 'a', 'b', 'c', 'd', 'e', 'f'}, false},
+		{"hex string", token.Hex(), token, false},
+		{"uppercase hex string", "4D88E15B60F486E428412DC9", token, false},
+		{"hex []byte", []byte(token.Hex()), token, false},
+		{"encoded string", s, token, false},
+		{"encoded []byte", []byte(s), token, false},
+		// These chars are both hex and String alphabet chars, hex wins.
+		{"hex and alphabet chars", "abcdefabcdefabcdefabcdef", Token{0xab, 0xcd, 0xef, 0xab, 0xcd, 0xef, 0xab, 0xcd, 0xef, 0xab, 0xcd, 0xef}, false},
+		// Only alphabet chars, still parsed as hex.
+		{"alphabet chars", s[:24], nilToken, true},
+		{"raw string", string(token.Bytes()), nilToken, true},
+		{"nil", nil, nilToken, true},
+		{"nil []byte", []byte(nil), nilToken, true},
+		{"empty string", "", nilToken, true},
+		{"empty []byte", []byte{}, nilToken, true},
+		{"compact string", token.CompactString(), nilToken, true},
+		{"invalid encoded string", "________________________________", nilToken, true},
+		{"int", 42, nilToken, true},
+		{"*Token", &token, nilToken, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAny(tt.v)
+			if tt.err != (err != nil) {
+				t.Fatalf("ParseAny() err = %v, want err %v", err, tt.err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("ParseAny() err = %v, want %v", err, ErrInvalidToken)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAny() = %x, want %x", got[:], tt.want[:])
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package xtoken
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// maxCounter is the largest value of the 3-byte counter.
+const maxCounter = 1<<24 - 1
+
+// NewFromParts assembles a Token from its components, it's meant to build
+// deterministic fixtures. It returns a *TimeOutOfRangeError when t can't be stored
+// in the 4-byte timestamp and ErrCounterOutOfRange when counter doesn't fit in
+// 3 bytes.
+func NewFromParts(t time.Time, machine [3]byte, pid uint16, counter uint32) (Token, error) {
+	var token Token
+	secs, err := checkedSeconds(t, 0)
+	if err != nil {
+		return token, err
+	}
+	if counter > maxCounter {
+		return token, ErrCounterOutOfRange
+	}
+	binary.BigEndian.PutUint32(token[:], secs)
+	copy(token[4:7], machine[:])
+	binary.BigEndian.PutUint16(token[7:9], pid)
+	token[9] = byte(counter >> 16)
+	token[10] = byte(counter >> 8)
+	token[11] = byte(counter)
+	return token, nil
+}
+
+// Parts returns all the components of the token, it's the inverse of NewFromParts.
+func (token Token) Parts() (t time.Time, machine [3]byte, pid uint16, counter uint32) {
+	copy(machine[:], token.Machine())
+	return token.Time(), machine, token.Pid(), uint32(token.Counter())
+}
+
+// Decomposed holds the components of a token, see Token.Decompose. It
+// marshals to JSON as an object with the time in RFC 3339 and the machine id
+// in hex.
+type Decomposed struct {
+	Time    time.Time `json:"time"`
+	Machine MachineID `json:"machine"`
+	Pid     uint16    `json:"pid"`
+	Counter uint32    `json:"counter"`
+}
+
+// Decompose returns the components of the token, with its time in UTC, e.g. to
+// show them in debugging tools.
+func (token Token) Decompose() Decomposed {
+	return Decomposed{
+		Time:    token.Time().UTC(),
+		Machine: token.MachineID(),
+		Pid:     token.Pid(),
+		Counter: uint32(token.Counter()),
+	}
+}
+
+// String returns the components on a line, e.g.
+// "time=2011-03-22T17:50:19Z machine=60f486 pid=58408 counter=4271561".
+func (d Decomposed) String() string {
+	return fmt.Sprintf("time=%s machine=%s pid=%d counter=%d", d.Time.Format(time.RFC3339), d.Machine, d.Pid, d.Counter)
+}
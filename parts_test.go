@@ -0,0 +1,78 @@
+package xtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewFromParts(t *testing.T) {
+	for _, v := range IDs {
+		var machine [3]byte
+		copy(machine[:], v.machine)
+		got, err := NewFromParts(v.token.Time(), machine, v.token.Pid(), uint32(v.token.Counter()))
+		if err != nil {
+			t.Fatalf("NewFromParts() err: %v", err)
+		}
+		if got != v.token {
+			t.Errorf("NewFromParts() = %x, want %x", got[:], v.token[:])
+		}
+
+		tm, m, pid, counter := v.token.Parts()
+		if tm.Unix() != v.timestamp || m != machine || pid != v.pid || counter != uint32(v.counter) {
+			t.Errorf("Parts() = %v, %x, %x, %d, want %v, %x, %x, %d",
+				tm, m, pid, counter, time.Unix(v.timestamp, 0), machine, v.pid, v.counter)
+		}
+		if got, err := NewFromParts(v.token.Parts()); err != nil || got != v.token {
+			t.Errorf("NewFromParts(Parts()) = %x, %v, want %x", got[:], err, v.token[:])
+		}
+	}
+}
+
+func TestNewFromPartsOutOfRange(t *testing.T) {
+	tests := []struct {
+		t       time.Time
+		counter uint32
+		err     error
+	}{
+		{time.Unix(1<<32-1, 0), maxCounter, nil},
+		{time.Unix(0, 0), 0, nil},
+		{time.Unix(-1, 0), 0, ErrTimeOutOfRange},
+		{time.Unix(1<<32, 0), 0, ErrTimeOutOfRange},
+		{time.Unix(0, 0), maxCounter + 1, ErrCounterOutOfRange},
+	}
+	for _, tt := range tests {
+		token, err := NewFromParts(tt.t, [3]byte{1, 2, 3}, 4, tt.counter)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("NewFromParts(%v, %d) err = %v, want %v", tt.t.Unix(), tt.counter, err, tt.err)
+		}
+		if err != nil && !token.IsZero() {
+			t.Errorf("NewFromParts(%v, %d) = %x, want the nil token", tt.t.Unix(), tt.counter, token[:])
+		}
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	token := Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	d := token.Decompose()
+	want := Decomposed{
+		Time:    time.Date(2011, 3, 22, 17, 50, 19, 0, time.UTC),
+		Machine: MachineID{0x60, 0xf4, 0x86},
+		Pid:     58408,
+		Counter: 4271561,
+	}
+	if d != want {
+		t.Errorf("Decompose() = %+v, want %+v", d, want)
+	}
+	if got, want := d.String(), "time=2011-03-22T17:50:19Z machine=60f486 pid=58408 counter=4271561"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("json.Marshal() err: %v", err)
+	}
+	if got, want := string(b), `{"time":"2011-03-22T17:50:19Z","machine":"60f486","pid":58408,"counter":4271561}`; got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
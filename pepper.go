@@ -0,0 +1,88 @@
+package xtoken
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const (
+	pepperRounds   = 4         // Feistel rounds
+	pepperHalfBits = 28        // bits of each half of the obfuscated 56 bits
+	pepperHalfMask = 1<<28 - 1 // mask of a half
+)
+
+// Pepper obfuscates the machine id, pid and counter of tokens with a secret,
+// so that clients can't read them, see WithPepper. The timestamp and the tag
+// are left as is, so Time and Tag keep working without the secret; Reveal
+// gives back the token to read the other components from. It's safe for
+// concurrent use.
+//
+// The 7 bytes are permuted with a 4-round Feistel network keyed by the secret
+// and tweaked by the timestamp, so tokens stay unique but the ones of a second
+// no longer sort by their counter.
+type Pepper struct {
+	block cipher.Block
+}
+
+// NewPepper returns a Pepper with secret, of any length.
+func NewPepper(secret []byte) *Pepper {
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		// A 32-byte key is always valid.
+		panic(err)
+	}
+	return &Pepper{block: block}
+}
+
+// Obfuscate returns token with its machine id, pid and counter obfuscated.
+func (p *Pepper) Obfuscate(token Token) Token {
+	v := pepperBits(token)
+	l, r := v>>pepperHalfBits, v&pepperHalfMask
+	// The block escapes through cipher.Block, share it between the rounds.
+	var b [aes.BlockSize]byte
+	for i := 0; i < pepperRounds; i++ {
+		l, r = r, l^p.round(&b, i, token, r)
+	}
+	return withPepperBits(token, l<<pepperHalfBits|r)
+}
+
+// Reveal returns the token that Obfuscate turned into token.
+func (p *Pepper) Reveal(token Token) Token {
+	v := pepperBits(token)
+	l, r := v>>pepperHalfBits, v&pepperHalfMask
+	var b [aes.BlockSize]byte
+	for i := pepperRounds - 1; i >= 0; i-- {
+		l, r = r^p.round(&b, i, token, l), l
+	}
+	return withPepperBits(token, l<<pepperHalfBits|r)
+}
+
+// round returns the round function of the Feistel network for half, tweaked
+// by the timestamp of token, b is a scratch block.
+func (p *Pepper) round(b *[aes.BlockSize]byte, i int, token Token, half uint64) uint64 {
+	*b = [aes.BlockSize]byte{}
+	b[0] = byte(i)
+	copy(b[1:5], token[0:4])
+	binary.BigEndian.PutUint32(b[5:9], uint32(half))
+	p.block.Encrypt(b[:], b[:])
+	return uint64(binary.BigEndian.Uint32(b[:4])) & pepperHalfMask
+}
+
+// pepperBits returns the 7 bytes of token obfuscated by a Pepper, all but the
+// timestamp and the tag.
+func pepperBits(token Token) uint64 {
+	return uint64(token[4])<<48 | uint64(token[5])<<40 | uint64(token[7])<<32 |
+		uint64(binary.BigEndian.Uint32(token[8:12]))
+}
+
+// withPepperBits returns token with the 7 bytes of pepperBits set to v.
+func withPepperBits(token Token, v uint64) Token {
+	token[4] = byte(v >> 48)
+	token[5] = byte(v >> 40)
+	token[7] = byte(v >> 32)
+	binary.BigEndian.PutUint32(token[8:12], uint32(v))
+	return token
+}
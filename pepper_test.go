@@ -0,0 +1,68 @@
+package xtoken
+
+import (
+	"testing"
+)
+
+func TestPepper(t *testing.T) {
+	p := NewPepper([]byte("secret"))
+	for _, v := range IDs {
+		got := p.Obfuscate(v.token)
+		if got == v.token {
+			t.Errorf("Obfuscate(%x) didn't change the token", v.token[:])
+		}
+		if got.Time() != v.token.Time() || got.Tag() != v.token.Tag() {
+			t.Errorf("Obfuscate(%x) = %x, want the same time and tag", v.token[:], got[:])
+		}
+		if back := p.Reveal(got); back != v.token {
+			t.Errorf("Reveal(Obfuscate(%x)) = %x", v.token[:], back[:])
+		}
+		if other := NewPepper([]byte("other secret")); other.Obfuscate(v.token) == got {
+			t.Errorf("Obfuscate(%x) is the same with another secret", v.token[:])
+		}
+	}
+}
+
+func TestPepperUnique(t *testing.T) {
+	p := NewPepper([]byte("secret"))
+	token := IDs[0].token
+	seen := make(map[Token]bool)
+	for i := 0; i < 1<<16; i++ {
+		token[10], token[11] = byte(i>>8), byte(i)
+		got := p.Obfuscate(token)
+		if seen[got] {
+			t.Fatalf("Obfuscate(%x) = %x, already returned", token[:], got[:])
+		}
+		seen[got] = true
+	}
+}
+
+func TestGeneratorWithPepper(t *testing.T) {
+	p := NewPepper([]byte("secret"))
+	g := NewGenerator(WithPepper(p), WithPid(0x1234), WithMachineID([]byte("host")))
+	plain := NewGenerator(WithPid(0x1234), WithMachineID([]byte("host")))
+	want := plain.New()
+	for i := 0; i < 100; i++ {
+		token := g.New()
+		if token.Pid() == 0x1234 && token.MachineID() == want.MachineID() {
+			t.Fatalf("New() = %x exposes its machine id and pid", token[:])
+		}
+		got := p.Reveal(token)
+		if got.Pid() != 0x1234 || got.MachineID() != want.MachineID() {
+			t.Errorf("Reveal(New()) = %x, want pid 1234 and machine id %v", got[:], want.MachineID())
+		}
+	}
+	tagged := g.NewTagged(7)
+	if got := p.Reveal(tagged); tagged.Tag() != 7 || got.Tag() != 7 || got.Pid() != 0x1234 {
+		t.Errorf("Reveal(NewTagged(7)) = %x, want tag 7 and pid 1234", got[:])
+	}
+}
+
+func BenchmarkPepperObfuscate(b *testing.B) {
+	p := NewPepper([]byte("secret"))
+	token := IDs[0].token
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		token = p.Obfuscate(token)
+	}
+}
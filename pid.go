@@ -0,0 +1,29 @@
+package xtoken
+
+import "os"
+
+// SetPid overrides the process id embedded in subsequently generated tokens.
+// It's safe to call concurrently with generation.
+func SetPid(p uint16) {
+	pid.Store(uint32(p))
+}
+
+// CurrentPid returns the process id embedded in newly generated tokens, which
+// differs from os.Getpid when it's mixed with the cpuset in containers or
+// overridden by SetPid.
+func CurrentPid() uint16 {
+	return uint16(pid.Load())
+}
+
+// DisablePidMixing makes subsequently generated tokens embed the process id as
+// returned by os.Getpid, truncated to 2 bytes, instead of mixing it with the
+// cpuset in containers, so that Token.Pid can be correlated with processes.
+// Tokens of processes in different containers are more likely to share a pid.
+func DisablePidMixing() {
+	SetPid(uint16(os.Getpid()))
+}
+
+// EnablePidMixing restores the default pid, mixed with the cpuset in containers.
+func EnablePidMixing() {
+	SetPid(uint16(mixedPid()))
+}
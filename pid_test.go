@@ -0,0 +1,64 @@
+package xtoken
+
+import (
+	"os"
+	"sync"
+	"testing"
+)
+
+// withPid restores the default pid at the end of the test.
+func withPid(t *testing.T, p uint16) {
+	t.Helper()
+	SetPid(p)
+	t.Cleanup(EnablePidMixing)
+}
+
+func TestSetPid(t *testing.T) {
+	withPid(t, 0xBEEF)
+	if got := CurrentPid(); got != 0xBEEF {
+		t.Errorf("CurrentPid() = %#x, want %#x", got, 0xBEEF)
+	}
+	token, err := FromString(New().String())
+	if err != nil {
+		t.Fatalf("FromString() err: %v", err)
+	}
+	if got := token.Pid(); got != 0xBEEF {
+		t.Errorf("Pid() = %#x, want %#x", got, 0xBEEF)
+	}
+	if got := NewMilli().Pid(); got != 0xBEEF {
+		t.Errorf("NewMilli().Pid() = %#x, want %#x", got, 0xBEEF)
+	}
+}
+
+func TestDisablePidMixing(t *testing.T) {
+	t.Cleanup(EnablePidMixing)
+	DisablePidMixing()
+	if got, want := New().Pid(), uint16(os.Getpid()); got != want {
+		t.Errorf("Pid() = %d, want %d", got, want)
+	}
+	EnablePidMixing()
+	if got, want := New().Pid(), uint16(mixedPid()); got != want {
+		t.Errorf("Pid() = %d, want %d", got, want)
+	}
+}
+
+func TestSetPidConcurrent(t *testing.T) {
+	t.Cleanup(EnablePidMixing)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_ = New()
+			}
+		}()
+		go func(p uint16) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetPid(p)
+			}
+		}(uint16(i))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,129 @@
+package xtoken
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ErrPrefixMismatch is returned when a prefixed Token doesn't have the expected prefix.
+	ErrPrefixMismatch strErr = "Token prefix mismatch"
+
+	// ErrInvalidPrefix is returned when a prefix is empty or contains characters outside of [a-z0-9].
+	ErrInvalidPrefix strErr = "invalid Token prefix"
+
+	// ErrUnknownPrefix is returned by Prefixer.Parse when a prefixed Token is
+	// well-formed but its prefix isn't one of the Prefixer.
+	ErrUnknownPrefix strErr = "unknown Token prefix"
+)
+
+// prefixSeparator separates the prefix from the encoded token.
+const prefixSeparator = '_'
+
+// StringWithPrefix returns prefix + "_" + the string representation of the
+// token, like Stripe-style typed identifiers such as "usr_…".
+// It panics if the prefix is invalid, see ValidatePrefix.
+func (token Token) StringWithPrefix(prefix string) string {
+	if err := ValidatePrefix(prefix); err != nil {
+		panic(fmt.Errorf("xtoken: invalid prefix %q", prefix))
+	}
+	text := make([]byte, len(prefix)+1+encodedLen)
+	copy(text, prefix)
+	text[len(prefix)] = prefixSeparator
+	StdEncoding.encode(text[len(prefix)+1:], token[:])
+	return string(text)
+}
+
+// ParsePrefixed reads a prefixed Token and returns its prefix. The encoded
+// token is the last 32 chars, since the alphabet contains '_' itself, and must
+// be preceded by the separator.
+func ParsePrefixed(s string) (prefix string, token Token, err error) {
+	if len(s) < encodedLen+1 || s[len(s)-encodedLen-1] != prefixSeparator {
+		return "", nilToken, ErrInvalidToken
+	}
+	prefix = s[:len(s)-encodedLen-1]
+	if err := ValidatePrefix(prefix); err != nil {
+		return "", nilToken, err
+	}
+	token, err = FromString(s[len(s)-encodedLen:])
+	if err != nil {
+		return "", nilToken, err
+	}
+	return prefix, token, nil
+}
+
+// ParseWithPrefix reads a prefixed Token and returns ErrPrefixMismatch unless
+// its prefix is wantPrefix.
+func ParseWithPrefix(s, wantPrefix string) (Token, error) {
+	prefix, token, err := ParsePrefixed(s)
+	if err != nil {
+		return nilToken, err
+	}
+	if prefix != wantPrefix {
+		return nilToken, ErrPrefixMismatch
+	}
+	return token, nil
+}
+
+// ValidatePrefix returns ErrInvalidPrefix unless prefix is a non-empty string
+// of [a-z0-9].
+func ValidatePrefix(prefix string) error {
+	if prefix == "" {
+		return ErrInvalidPrefix
+	}
+	if strings.IndexFunc(prefix, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) >= 0 {
+		return ErrInvalidPrefix
+	}
+	return nil
+}
+
+// Prefixer formats and parses the prefixed tokens of a fixed set of resource
+// types, such as "usr" and "ord", so that a token of any of them can be parsed
+// without knowing its type in advance. It's safe for concurrent use.
+type Prefixer struct {
+	prefixes map[string]struct{}
+}
+
+// NewPrefixer returns a Prefixer for prefixes, it panics if one of them is
+// invalid, see ValidatePrefix.
+func NewPrefixer(prefixes ...string) *Prefixer {
+	p := &Prefixer{prefixes: make(map[string]struct{}, len(prefixes))}
+	for _, prefix := range prefixes {
+		if err := ValidatePrefix(prefix); err != nil {
+			panic(fmt.Errorf("xtoken: invalid prefix %q", prefix))
+		}
+		p.prefixes[prefix] = struct{}{}
+	}
+	return p
+}
+
+// Format returns the token with prefix, see StringWithPrefix. It panics if
+// prefix isn't one of p.
+func (p *Prefixer) Format(prefix string, token Token) string {
+	if !p.Known(prefix) {
+		panic(fmt.Errorf("xtoken: unknown prefix %q", prefix))
+	}
+	return token.StringWithPrefix(prefix)
+}
+
+// Parse reads a prefixed Token like ParsePrefixed and returns its prefix, the
+// resource type of the token. It returns ErrUnknownPrefix when the prefix
+// isn't one of p.
+func (p *Prefixer) Parse(s string) (prefix string, token Token, err error) {
+	prefix, token, err = ParsePrefixed(s)
+	if err != nil {
+		return "", nilToken, err
+	}
+	if !p.Known(prefix) {
+		return "", nilToken, ErrUnknownPrefix
+	}
+	return prefix, token, nil
+}
+
+// Known reports whether prefix is one of p.
+func (p *Prefixer) Known(prefix string) bool {
+	_, ok := p.prefixes[prefix]
+	return ok
+}
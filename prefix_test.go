@@ -0,0 +1,135 @@
+package xtoken
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStringWithPrefix(t *testing.T) {
+	for _, token := range []Token{New(), nilToken, IDs[0].token} {
+		s := token.StringWithPrefix("usr")
+		if len(s) != len("usr_")+encodedLen || s[:4] != "usr_" {
+			t.Errorf("StringWithPrefix() = %q, want usr_ + 32 chars", s)
+		}
+		prefix, got, err := ParsePrefixed(s)
+		if err != nil {
+			t.Fatalf("ParsePrefixed(%q) err: %v", s, err)
+		}
+		if prefix != "usr" || got != token {
+			t.Errorf("ParsePrefixed(%q) = %q, %v, want %q, %v", s, prefix, got, "usr", token)
+		}
+		if got, err := ParseWithPrefix(s, "usr"); err != nil || got != token {
+			t.Errorf("ParseWithPrefix(%q) = %v, %v, want %v", s, got, err, token)
+		}
+		if _, err := ParseWithPrefix(s, "ord"); err != ErrPrefixMismatch {
+			t.Errorf("ParseWithPrefix(%q, ord) err = %v, want %v", s, err, ErrPrefixMismatch)
+		}
+	}
+}
+
+func TestParsePrefixedUnderscores(t *testing.T) {
+	// The encoded token may contain '_' itself, only the last 32 chars are the token.
+	token, err := NewFromParts(time.Unix(math.MaxUint32, 0), [3]byte{0xFF, 0xFF, 0xFF}, math.MaxUint16, maxCounter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// All bits set make '_' chars, the canonical layout with its first two
+	// value chars swapped starts and ends with one.
+	order := valuePositions
+	order[0], order[1] = order[1], order[0]
+	var text [encodedLen]byte
+	StdEncoding.encodeOrder(text[:], token[:], order[:])
+	s := string(text[:])
+	if s[0] != '_' || s[encodedLen-1] != '_' {
+		t.Fatalf("encoding %q doesn't start and end with '_'", s)
+	}
+	if got, err := FromString(s); err != nil || got != token {
+		t.Fatalf("FromString(%q) = %v, %v, want %v", s, got, err, token)
+	}
+	prefix, got, err := ParsePrefixed("sess_" + s)
+	if err != nil || prefix != "sess" || got != token {
+		t.Errorf("ParsePrefixed() = %q, %v, %v, want sess, %v", prefix, got, err, token)
+	}
+	// "a_b" is not a valid prefix.
+	if _, _, err := ParsePrefixed("a_b_" + s); err != ErrInvalidPrefix {
+		t.Errorf("ParsePrefixed() err = %v, want %v", err, ErrInvalidPrefix)
+	}
+}
+
+func TestParsePrefixedInvalid(t *testing.T) {
+	s := New().String()
+	tests := []struct {
+		in  string
+		err error
+	}{
+		{"", ErrInvalidToken},
+		{s, ErrInvalidToken},          // no prefix
+		{"_" + s, ErrInvalidPrefix},   // empty prefix
+		{"usr" + s, ErrInvalidToken},  // missing separator
+		{"usr-" + s, ErrInvalidToken}, // wrong separator
+		{"Usr_" + s, ErrInvalidPrefix},
+		{"us r_" + s, ErrInvalidPrefix},
+		{"usr_" + s[1:], ErrInvalidToken},
+		{"usr_" + s[1:] + "!", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		if _, _, err := ParsePrefixed(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("ParsePrefixed(%q) err = %v, want %v", tt.in, err, tt.err)
+		}
+	}
+}
+
+func TestStringWithPrefixInvalid(t *testing.T) {
+	for _, prefix := range []string{"", "a_b", "USR", "usr!"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("StringWithPrefix(%q) didn't panic", prefix)
+				}
+			}()
+			New().StringWithPrefix(prefix)
+		}()
+	}
+}
+
+func TestPrefixer(t *testing.T) {
+	p := NewPrefixer("usr", "ord")
+	usr, ord := New(), New()
+	for _, tt := range []struct {
+		prefix string
+		token  Token
+	}{{"usr", usr}, {"ord", ord}} {
+		s := p.Format(tt.prefix, tt.token)
+		prefix, got, err := p.Parse(s)
+		if err != nil || prefix != tt.prefix || got != tt.token {
+			t.Errorf("Parse(%q) = %q, %v, %v, want %q, %v", s, prefix, got, err, tt.prefix, tt.token)
+		}
+	}
+	if _, _, err := p.Parse(usr.StringWithPrefix("sess")); err != ErrUnknownPrefix {
+		t.Errorf("Parse() of an unknown prefix err = %v, want %v", err, ErrUnknownPrefix)
+	}
+	if _, _, err := p.Parse(usr.String()); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Parse() of an unprefixed token err = %v, want %v", err, ErrInvalidToken)
+	}
+	if p.Known("sess") || !p.Known("usr") {
+		t.Errorf("Known() = %v, %v, want false, true", p.Known("sess"), p.Known("usr"))
+	}
+}
+
+func TestPrefixerPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"invalid prefix": func() { NewPrefixer("usr", "Ord") },
+		"unknown prefix": func() { NewPrefixer("usr").Format("ord", New()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("didn't panic")
+				}
+			}()
+			f()
+		})
+	}
+}
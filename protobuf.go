@@ -0,0 +1,47 @@
+package xtoken
+
+// The methods of this file make Token a gogoproto custom type for bytes
+// fields, with the same shape as the Token message of xtokenpb:
+//
+//	bytes id = 1 [(gogoproto.customtype) = "github.com/zdz1715/xtoken.Token", (gogoproto.nullable) = false];
+//
+// The nil token is stored as an empty field, which is the default value of a
+// bytes field, and any other token as its 12 raw bytes.
+
+// Size returns the length of the field holding the token, 0 for the nil token.
+func (token Token) Size() int {
+	if token.IsZero() {
+		return 0
+	}
+	return rawLen
+}
+
+// Marshal returns the field holding the token, see Size.
+func (token Token) Marshal() ([]byte, error) {
+	if token.IsZero() {
+		return []byte{}, nil
+	}
+	return token.MarshalBinary()
+}
+
+// MarshalTo copies the field holding the token to data, which must be at least
+// Size bytes long, and returns the number of bytes copied.
+func (token Token) MarshalTo(data []byte) (int, error) {
+	if token.IsZero() {
+		return 0, nil
+	}
+	if len(data) < rawLen {
+		return 0, &InvalidLengthError{Got: len(data), Want: rawLen}
+	}
+	return copy(data, token[:]), nil
+}
+
+// Unmarshal reads a field returned by Marshal, it must be empty or hold the
+// 12 raw bytes of a token.
+func (token *Token) Unmarshal(data []byte) error {
+	if len(data) == 0 {
+		*token = nilToken
+		return nil
+	}
+	return token.UnmarshalBinary(data)
+}
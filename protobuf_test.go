@@ -0,0 +1,37 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProtobufCustomType(t *testing.T) {
+	for _, token := range []Token{nilToken, IDs[0].token, New()} {
+		b, err := token.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal() err: %v", err)
+		}
+		if len(b) != token.Size() {
+			t.Errorf("len(Marshal()) = %d, want Size() %d", len(b), token.Size())
+		}
+		buf := make([]byte, rawLen)
+		n, err := token.MarshalTo(buf)
+		if err != nil || n != token.Size() || string(buf[:n]) != string(b) {
+			t.Errorf("MarshalTo() = %d, %v, want %x", n, err, b)
+		}
+		got := New()
+		if err := got.Unmarshal(b); err != nil || got != token {
+			t.Errorf("Unmarshal(%x) = %v, %v, want %v", b, got, err, token)
+		}
+	}
+	if nilToken.Size() != 0 {
+		t.Errorf("Size() of the nil token = %d, want 0", nilToken.Size())
+	}
+	var token Token
+	if err := token.Unmarshal(make([]byte, 5)); !errors.Is(err, ErrBadLength) {
+		t.Errorf("Unmarshal() of 5 bytes err = %v, want %v", err, ErrBadLength)
+	}
+	if _, err := New().MarshalTo(make([]byte, 5)); !errors.Is(err, ErrBadLength) {
+		t.Errorf("MarshalTo() of 5 bytes err = %v, want %v", err, ErrBadLength)
+	}
+}
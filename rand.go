@@ -0,0 +1,59 @@
+package xtoken
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// readerBox wraps an io.Reader so it can be stored in an atomic.Pointer.
+type readerBox struct {
+	r io.Reader
+}
+
+// randReader is the source of randomness set by SetRandReader, nil when
+// crypto/rand.Reader is used.
+var randReader atomic.Pointer[readerBox]
+
+// SetRandReader replaces the source of randomness used to seed the counter, the
+// random counter step of a Generator and the fallback machine id, nil restores
+// crypto/rand.Reader. It affects subsequent Generator construction, the counter
+// and the machine id of the process are drawn once when the package is loaded.
+// NewRandom always reads from crypto/rand.
+func SetRandReader(r io.Reader) {
+	if r == nil {
+		randReader.Store(nil)
+		return
+	}
+	randReader.Store(&readerBox{r: r})
+}
+
+// readRand fills b from the source of randomness.
+func readRand(b []byte) error {
+	r := rand.Reader
+	if box := randReader.Load(); box != nil {
+		r = box.r
+	}
+	_, err := io.ReadFull(r, b)
+	return err
+}
+
+// randInt generates a random 3-byte uint32.
+func randInt() (uint32, error) {
+	var b [3]byte
+	if err := readRand(b[:]); err != nil {
+		return 0, fmt.Errorf("xtoken: cannot generate random number: %w", err)
+	}
+	return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]), nil
+}
+
+// mustRandInt is like randInt but panics on failure, it's only used to seed the
+// counter when the package is loaded.
+func mustRandInt() uint32 {
+	i, err := randInt()
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
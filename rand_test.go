@@ -0,0 +1,70 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var errReader = errors.New("reader failed")
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) { return 0, errReader }
+
+// withRandReader makes the package read randomness from r until the end of the test.
+func withRandReader(t *testing.T, r io.Reader) {
+	t.Helper()
+	SetRandReader(r)
+	t.Cleanup(func() { SetRandReader(nil) })
+}
+
+func TestSetRandReader(t *testing.T) {
+	withRandReader(t, bytes.NewReader([]byte{0x12, 0x34, 0x56, 1, 2, 3, 4, 5, 6, 7, 8}))
+	i, err := randInt()
+	if err != nil {
+		t.Fatalf("randInt() err: %v", err)
+	}
+	if i != 0x123456 {
+		t.Errorf("randInt() = %#x, want %#x", i, 0x123456)
+	}
+	g, err := NewGeneratorE(WithRandomCounterStep(16))
+	if err != nil {
+		t.Fatalf("NewGeneratorE() err: %v", err)
+	}
+	if want := uint64(0x0102030405060708); g.rng != want {
+		t.Errorf("rng = %#x, want %#x", g.rng, want)
+	}
+}
+
+func TestSetRandReaderFailure(t *testing.T) {
+	withRandReader(t, failingReader{})
+	if _, err := randInt(); !errors.Is(err, errReader) {
+		t.Errorf("randInt() err = %v, want %v", err, errReader)
+	}
+	g, err := NewGeneratorE(WithRandomCounterStep(16))
+	if !errors.Is(err, errReader) || g != nil {
+		t.Errorf("NewGeneratorE() = %v, %v, want nil, %v", g, err, errReader)
+	}
+	// Without random step, nothing is drawn.
+	if _, err := NewGeneratorE(); err != nil {
+		t.Errorf("NewGeneratorE() err: %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("NewGenerator() didn't panic")
+			}
+		}()
+		NewGenerator(WithRandomCounterStep(16))
+	}()
+}
+
+func TestSetRandReaderNil(t *testing.T) {
+	SetRandReader(failingReader{})
+	SetRandReader(nil)
+	if _, err := randInt(); err != nil {
+		t.Errorf("randInt() err: %v after restoring crypto/rand", err)
+	}
+}
@@ -0,0 +1,48 @@
+package xtoken
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// RandomTag is the tag of the tokens generated by NewRandom, it tells them
+// apart from the tokens of New, see Token.IsRandom. It's reserved: NewTagged
+// panics with it.
+const RandomTag = 0xFF
+
+// NewRandom generates a Token which keeps the 4-byte timestamp but fills the
+// machine id, pid and counter bytes with crypto/rand output, except for the tag
+// set to RandomTag, so it doesn't leak anything about the process which
+// generated it: 64 bits are random.
+// Machine, Pid and Counter return meaningless values on such tokens, the raw
+// layout and the string encoding are the same as New.
+func NewRandom() Token {
+	return NewRandomWithTime(time.Now())
+}
+
+// NewRandomWithTime is like NewRandom with the passed in time.
+func NewRandomWithTime(t time.Time) Token {
+	return randomToken(uint32(t.Unix()))
+}
+
+// randomToken returns a token of NewRandom with the timestamp secs.
+func randomToken(secs uint32) Token {
+	var token Token
+	binary.BigEndian.PutUint32(token[:], secs)
+	if _, err := rand.Read(token[4:]); err != nil {
+		panic(fmt.Errorf("xtoken: cannot generate random number: %v", err))
+	}
+	token[tagIndex] = RandomTag
+	return token
+}
+
+// IsRandom reports whether the token was generated by NewRandom, that is its
+// tag is RandomTag, which New and NewTagged never issue.
+// Tokens generated by versions of the package before tags carry the last byte
+// of their machine id instead, the ones of hosts whose byte is 0xFF are
+// reported random too.
+func (token Token) IsRandom() bool {
+	return token[tagIndex] == RandomTag
+}
@@ -0,0 +1,51 @@
+package xtoken
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRandom(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := NewRandomWithTime(now)
+	if got := token.Time(); !got.Equal(now) {
+		t.Errorf("Time() = %v, want %v", got, now)
+	}
+	got, err := FromString(token.String())
+	if err != nil {
+		t.Fatalf("FromString() err: %v", err)
+	}
+	if got != token {
+		t.Errorf("FromString() = %x, want %x", got[:], token[:])
+	}
+	if !token.IsRandom() || token.Tag() != RandomTag {
+		t.Errorf("IsRandom() = false, Tag() = %d, want true, %d", token.Tag(), RandomTag)
+	}
+	if New().IsRandom() || NewTagged(1).IsRandom() {
+		t.Error("IsRandom() = true for a token of New")
+	}
+}
+
+func TestNewRandomUniqueness(t *testing.T) {
+	n := 2000000
+	if testing.Short() {
+		n = 100000
+	}
+	seen := make(map[Token]struct{}, n)
+	for i := 0; i < n; i++ {
+		token := NewRandom()
+		if _, ok := seen[token]; ok {
+			t.Fatalf("NewRandom() generated a duplicate after %d tokens", i)
+		}
+		seen[token] = struct{}{}
+	}
+}
+
+func BenchmarkNewRandom(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = NewRandom()
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package xtoken
+
+import "sync/atomic"
+
+// Reinitialize derives again the identity embedded in new tokens, as it's done
+// when the package is loaded: the machine id from MachineIDEnv or the platform
+// sources, the pid and its cpuset mix, and a random counter seed. It also drops
+// any override set by SetMachineID, SetPid or DisablePidMixing.
+//
+// Call it when the process resumes from a VM snapshot or a container
+// checkpoint (e.g. CRIU), so that clones of the same snapshot don't generate
+// the same tokens. It's safe to call concurrently with the New* functions.
+// Tokens generated in the current second before and after the call may
+// collide with a negligible probability since the counter starts over from a
+// random value, and Generators using WithShardedCounter finish the counter
+// blocks they already reserved.
+func Reinitialize() error {
+	id, err := readMachineID()
+	if err != nil {
+		return err
+	}
+	seed, err := randInt()
+	if err != nil {
+		return err
+	}
+	machineID.Store((*[4]byte)(id))
+	pid.Store(uint32(uint16(mixedPid())))
+	atomic.StoreUint32(&objectIDCounter, seed)
+	// Make the Generators open a new counter window, the previous ones refer
+	// to the previous seed.
+	atomic.AddUint32(&counterGeneration, 1)
+	return nil
+}
@@ -0,0 +1,94 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReinitialize(t *testing.T) {
+	t.Cleanup(func() {
+		if err := Reinitialize(); err != nil {
+			t.Errorf("Reinitialize() err: %v", err)
+		}
+	})
+	want, err := readMachineID()
+	if err != nil {
+		t.Fatalf("readMachineID() err: %v", err)
+	}
+	// Simulate a clone resuming with a stale identity.
+	machineID.Store(&[4]byte{0xAA, 0xBB, 0xCC, 0xDD})
+	SetPid(0xBEEF)
+
+	withRandReader(t, bytes.NewReader([]byte{0x12, 0x34, 0x56}))
+	if err := Reinitialize(); err != nil {
+		t.Fatalf("Reinitialize() err: %v", err)
+	}
+	token := New()
+	if got := token.Machine(); !bytes.Equal(got[:2], want[:2]) {
+		t.Errorf("Machine() = %x, want %x", got[:2], want[:2])
+	}
+	if got, want := token.Pid(), uint16(mixedPid()); got != want {
+		t.Errorf("Pid() = %d, want %d", got, want)
+	}
+	if got := token.Counter(); got != 0x123457 {
+		t.Errorf("Counter() = %#x, want %#x", got, 0x123457)
+	}
+}
+
+func TestReinitializeCounterWindow(t *testing.T) {
+	t.Cleanup(func() {
+		if err := Reinitialize(); err != nil {
+			t.Errorf("Reinitialize() err: %v", err)
+		}
+	})
+	secs := uint32(time.Now().Unix())
+	// The window of the previous seed would take the new one for a wrap.
+	next := uint32(0x123457)
+	setCounter(defaultGenerator, secs, next-1<<24, next)
+	withRandReader(t, bytes.NewReader([]byte{0x12, 0x34, 0x56}))
+	if err := Reinitialize(); err != nil {
+		t.Fatalf("Reinitialize() err: %v", err)
+	}
+	if _, err := defaultGenerator.newStrict(secs); err != nil {
+		t.Errorf("newStrict() err: %v", err)
+	}
+}
+
+func TestReinitializeFailure(t *testing.T) {
+	before := New()
+	withRandReader(t, failingReader{})
+	if err := Reinitialize(); !errors.Is(err, errReader) {
+		t.Fatalf("Reinitialize() err = %v, want %v", err, errReader)
+	}
+	// The identity is left untouched.
+	after := New()
+	if !bytes.Equal(after.Machine(), before.Machine()) || after.Pid() != before.Pid() {
+		t.Errorf("New() = %x after a failed Reinitialize, want the identity of %x", after[:], before[:])
+	}
+}
+
+func TestReinitializeConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_ = New()
+				_ = NewMilli()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if err := Reinitialize(); err != nil {
+					t.Errorf("Reinitialize() err: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
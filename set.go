@@ -0,0 +1,58 @@
+package xtoken
+
+import "slices"
+
+// TokenSet is a set of tokens, keyed by their 12 raw bytes rather than by
+// their string representation. The zero value is not usable, use make or
+// NewTokenSet.
+type TokenSet map[Token]struct{}
+
+// NewTokenSet returns a set holding tokens.
+func NewTokenSet(tokens ...Token) TokenSet {
+	s := make(TokenSet, len(tokens))
+	s.AddAll(tokens...)
+	return s
+}
+
+// Add adds token to the set and reports whether it was missing.
+func (s TokenSet) Add(token Token) bool {
+	if _, ok := s[token]; ok {
+		return false
+	}
+	s[token] = struct{}{}
+	return true
+}
+
+// AddAll adds tokens to the set.
+func (s TokenSet) AddAll(tokens ...Token) {
+	for _, token := range tokens {
+		s[token] = struct{}{}
+	}
+}
+
+// Contains reports whether token is in the set.
+func (s TokenSet) Contains(token Token) bool {
+	_, ok := s[token]
+	return ok
+}
+
+// Delete removes token from the set.
+func (s TokenSet) Delete(token Token) {
+	delete(s, token)
+}
+
+// Len returns the number of tokens of the set.
+func (s TokenSet) Len() int {
+	return len(s)
+}
+
+// Sorted returns the tokens of the set in increasing order, in a slice of
+// exactly Len tokens.
+func (s TokenSet) Sorted() []Token {
+	tokens := make([]Token, 0, len(s))
+	for token := range s {
+		tokens = append(tokens, token)
+	}
+	slices.SortFunc(tokens, Compare)
+	return tokens
+}
@@ -0,0 +1,54 @@
+package xtoken
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenSet(t *testing.T) {
+	a, b, c := New(), New(), New()
+	s := NewTokenSet(c, a)
+	if !s.Add(b) || s.Add(b) {
+		t.Error("Add() doesn't report whether the token was missing")
+	}
+	s.AddAll(a, b)
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", s.Len())
+	}
+	if !s.Contains(a) || !s.Contains(b) || !s.Contains(c) || s.Contains(New()) {
+		t.Error("Contains() doesn't match the tokens added")
+	}
+	sorted := s.Sorted()
+	if want := []Token{a, b, c}; !slices.Equal(sorted, want) {
+		t.Errorf("Sorted() = %v, want %v", sorted, want)
+	}
+	if cap(sorted) != s.Len() {
+		t.Errorf("cap(Sorted()) = %d, want %d", cap(sorted), s.Len())
+	}
+	s.Delete(b)
+	if s.Contains(b) || s.Len() != 2 {
+		t.Errorf("Delete() left %v", s.Sorted())
+	}
+}
+
+func BenchmarkTokenSet(b *testing.B) {
+	tokens := NewBatch(100000)
+	b.Run("TokenSet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s := make(TokenSet)
+			for _, token := range tokens {
+				s.Add(token)
+			}
+		}
+	})
+	b.Run("StringSet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s := make(map[string]struct{})
+			for _, token := range tokens {
+				s[token.String()] = struct{}{}
+			}
+		}
+	})
+}
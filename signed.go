@@ -0,0 +1,190 @@
+package xtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+const (
+	// ErrBadSignature is returned when the signature of a signed Token doesn't
+	// match its text with any key, e.g. because a char of either was altered.
+	ErrBadSignature strErr = "bad Token signature"
+)
+
+const (
+	signatureLen        = 8                                // truncated HMAC-SHA256 len
+	signatureEncodedLen = 11                               // signature encoded len
+	signedEncodedLen    = encodedLen + signatureEncodedLen // signed string encoded len
+
+	minSignatureLen = 4           // shortest signature of a Signer
+	maxSignatureLen = sha256.Size // longest signature of a Signer
+
+	maxSignatureEncodedLen = (8*(1+maxSignatureLen) + 5) / 6 // longest encoded signature, with a key id
+)
+
+// signatureEncoding encodes signatures with the token alphabet, Strict rejects
+// non-zero padding bits so a signature has a single valid encoding.
+var signatureEncoding = base64.NewEncoding(encoding).WithPadding(base64.NoPadding).Strict()
+
+// NewSigned generates a globally unique Token and returns its signed string
+// representation, see Token.SignedString.
+func NewSigned(key []byte) string {
+	return New().SignedString(key)
+}
+
+// SignedString returns the string representation of the token followed by an
+// HMAC-SHA256 of that representation with key, truncated to 8 bytes and
+// encoded with the same alphabet: 43 chars in total.
+func (token Token) SignedString(key []byte) string {
+	return signedString(token, key, signatureLen)
+}
+
+// ParseSigned reads a Token from its signed string representation and verifies
+// its signature against keys, pass the current key followed by the previous ones
+// to rotate keys. The signature is verified over the text before the token is
+// decoded, so any altered char fails with ErrBadSignature. It returns
+// ErrInvalidToken for input of the wrong length.
+func ParseSigned(s string, keys ...[]byte) (Token, error) {
+	return parseSigned(s, signatureLen, keys)
+}
+
+// Signer signs tokens and verifies them with a key and a signature length
+// other than the 8 bytes of SignedString, or with the keys of a Keyring. It's
+// safe for concurrent use.
+type Signer struct {
+	key  []byte
+	ring *Keyring
+	size int
+}
+
+// SignerOption configures a Signer.
+type SignerOption func(s *Signer)
+
+// WithSignatureSize sets the length of the signatures, an HMAC-SHA256
+// truncated to n bytes, from 4 to 32. It defaults to 8 bytes.
+// Longer signatures are harder to forge by brute force, at the cost of 4 chars
+// every 3 bytes.
+func WithSignatureSize(n int) SignerOption {
+	return func(s *Signer) {
+		s.size = n
+	}
+}
+
+// NewSigner returns a Signer with key configured with opts, it panics if the
+// signature size is out of range.
+func NewSigner(key []byte, opts ...SignerOption) *Signer {
+	s := &Signer{
+		key:  append([]byte(nil), key...),
+		size: signatureLen,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.size < minSignatureLen || s.size > maxSignatureLen {
+		panic(fmt.Sprintf("xtoken: signature size %d out of range [%d, %d]", s.size, minSignatureLen, maxSignatureLen))
+	}
+	return s
+}
+
+// NewKeyringSigner returns a Signer with the keys of ring configured with opts,
+// it panics if the signature size is out of range. The signatures are
+// preceded by the 1-byte id of their key, which adds 1 or 2 chars.
+func NewKeyringSigner(ring *Keyring, opts ...SignerOption) *Signer {
+	s := NewSigner(nil, opts...)
+	s.ring = ring
+	return s
+}
+
+// New generates a globally unique Token and returns its signed string
+// representation, see Signer.Sign.
+func (s *Signer) New() string {
+	return s.Sign(New())
+}
+
+// Sign returns the string representation of the token followed by its
+// signature, encoded with the same alphabet.
+func (s *Signer) Sign(token Token) string {
+	if s.ring == nil {
+		return signedString(token, s.key, s.size)
+	}
+	key := s.ring.currentKey()
+	var tag [1 + maxSignatureLen]byte
+	tag[0] = key.id
+	text := make([]byte, encodedLen+signatureEncoding.EncodedLen(1+s.size))
+	StdEncoding.encode(text, token[:])
+	copy(tag[1:], sign(text[:encodedLen], key.key)[:s.size])
+	signatureEncoding.Encode(text[encodedLen:], tag[:1+s.size])
+	return string(text)
+}
+
+// Verify reads a Token from a string returned by Sign and verifies its
+// signature over the text before decoding the token. It returns
+// ErrInvalidToken for input of the wrong length and ErrBadSignature when the
+// signature doesn't match, or ErrUnknownKey when its key isn't in the Keyring
+// of the Signer.
+func (s *Signer) Verify(str string) (Token, error) {
+	if s.ring == nil {
+		return parseSigned(str, s.size, [][]byte{s.key})
+	}
+	if want := encodedLen + signatureEncoding.EncodedLen(1+s.size); len(str) != want {
+		return nilToken, &InvalidLengthError{Got: len(str), Want: want}
+	}
+	text, signature := []byte(str[:encodedLen]), []byte(str[encodedLen:])
+	// Only the key id is read from the signature before it's verified.
+	var tag [1 + maxSignatureLen]byte
+	if m, err := signatureEncoding.Decode(tag[:], signature); err != nil || m != 1+s.size {
+		return nilToken, ErrBadSignature
+	}
+	key, err := s.ring.lookup(tag[0])
+	if err != nil {
+		return nilToken, err
+	}
+	copy(tag[1:], sign(text, key.key)[:s.size])
+	if !signatureMatches(signature, tag[:1+s.size]) {
+		return nilToken, ErrBadSignature
+	}
+	return FromString(str[:encodedLen])
+}
+
+// signedString returns the string representation of token followed by its
+// signature with key truncated to n bytes.
+func signedString(token Token, key []byte, n int) string {
+	text := make([]byte, encodedLen+signatureEncoding.EncodedLen(n))
+	StdEncoding.encode(text, token[:])
+	signatureEncoding.Encode(text[encodedLen:], sign(text[:encodedLen], key)[:n])
+	return string(text)
+}
+
+// parseSigned reads a Token from s, its signature truncated to n bytes must
+// match one of keys.
+func parseSigned(s string, n int, keys [][]byte) (Token, error) {
+	if want := encodedLen + signatureEncoding.EncodedLen(n); len(s) != want {
+		return nilToken, &InvalidLengthError{Got: len(s), Want: want}
+	}
+	text, signature := []byte(s[:encodedLen]), []byte(s[encodedLen:])
+	for _, key := range keys {
+		if signatureMatches(signature, sign(text, key)[:n]) {
+			return FromString(s[:encodedLen])
+		}
+	}
+	return nilToken, ErrBadSignature
+}
+
+// signatureMatches reports whether signature is the encoding of mac, it
+// compares the encoded forms in constant time so that a signature is rejected
+// before anything of it is decoded.
+func signatureMatches(signature, mac []byte) bool {
+	var want [maxSignatureEncodedLen]byte
+	n := signatureEncoding.EncodedLen(len(mac))
+	signatureEncoding.Encode(want[:n], mac)
+	return hmac.Equal(signature, want[:n])
+}
+
+// sign returns the HMAC-SHA256 of the text of a token with key.
+func sign(text, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(text)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,130 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSigned(t *testing.T) {
+	key := []byte("current key")
+	s := NewSigned(key)
+	if len(s) != signedEncodedLen {
+		t.Fatalf("len(NewSigned()) = %d, want %d", len(s), signedEncodedLen)
+	}
+	token, err := ParseSigned(s, key)
+	if err != nil {
+		t.Fatalf("ParseSigned() err: %v", err)
+	}
+	if got, err := FromString(s[:encodedLen]); err != nil || got != token {
+		t.Errorf("FromString() = %v, %v, want %v", got, err, token)
+	}
+	for i, v := range IDs {
+		got, err := ParseSigned(v.token.SignedString(key), key)
+		if err != nil {
+			t.Fatalf("IDs[%d] ParseSigned() err: %v", i, err)
+		}
+		if got != v.token {
+			t.Errorf("IDs[%d] ParseSigned() = %x, want %x", i, got[:], v.token[:])
+		}
+	}
+}
+
+func TestSignedKeys(t *testing.T) {
+	previous, current := []byte("previous key"), []byte("current key")
+	s := NewSigned(previous)
+	if _, err := ParseSigned(s, current); err != ErrBadSignature {
+		t.Errorf("ParseSigned() with wrong key err = %v, want %v", err, ErrBadSignature)
+	}
+	if _, err := ParseSigned(s); err != ErrBadSignature {
+		t.Errorf("ParseSigned() without keys err = %v, want %v", err, ErrBadSignature)
+	}
+	if _, err := ParseSigned(s, current, previous); err != nil {
+		t.Errorf("ParseSigned() with rotated keys err: %v", err)
+	}
+}
+
+func TestSignedTamper(t *testing.T) {
+	key := []byte("key")
+	token := New()
+	s := token.SignedString(key)
+	for i := 0; i < len(s); i++ {
+		for j := 0; j < len(encoding); j++ {
+			if encoding[j] == s[i] {
+				continue
+			}
+			tampered := s[:i] + string(encoding[j]) + s[i+1:]
+			// The signature covers the text, even the chars of the token
+			// which decode to the same raw bytes.
+			if _, err := ParseSigned(tampered, key); err != ErrBadSignature {
+				t.Fatalf("ParseSigned(%q) err = %v, want %v", tampered, err, ErrBadSignature)
+			}
+		}
+	}
+}
+
+func TestParseSignedInvalid(t *testing.T) {
+	key := []byte("key")
+	s := NewSigned(key)
+	for _, in := range []string{
+		"",
+		s[:encodedLen],
+		s + "a",
+	} {
+		if _, err := ParseSigned(in, key); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("ParseSigned(%q) err = %v, want %v", in, err, ErrInvalidToken)
+		}
+	}
+	// Invalid chars fail the signature before they are decoded.
+	for _, in := range []string{
+		s[:len(s)-1] + "!",
+		"!" + s[1:],
+	} {
+		if _, err := ParseSigned(in, key); err != ErrBadSignature {
+			t.Errorf("ParseSigned(%q) err = %v, want %v", in, err, ErrBadSignature)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	key := []byte("current key")
+	for _, size := range []int{minSignatureLen, signatureLen, 16, maxSignatureLen} {
+		signer := NewSigner(key, WithSignatureSize(size))
+		token := New()
+		s := signer.Sign(token)
+		if want := encodedLen + (size*8+5)/6; len(s) != want {
+			t.Errorf("size %d: len(Sign()) = %d, want %d", size, len(s), want)
+		}
+		got, err := signer.Verify(s)
+		if err != nil || got != token {
+			t.Errorf("size %d: Verify() = %v, %v, want %v", size, got, err, token)
+		}
+		if _, err := NewSigner([]byte("other key"), WithSignatureSize(size)).Verify(s); err != ErrBadSignature {
+			t.Errorf("size %d: Verify() with wrong key err = %v, want %v", size, err, ErrBadSignature)
+		}
+		if _, err := signer.Verify(signer.New()); err != nil {
+			t.Errorf("size %d: Verify(New()) err: %v", size, err)
+		}
+	}
+
+	// The default Signer matches SignedString.
+	s := NewSigned(key)
+	if _, err := NewSigner(key).Verify(s); err != nil {
+		t.Errorf("Verify(NewSigned()) err: %v", err)
+	}
+	if _, err := NewSigner(key, WithSignatureSize(16)).Verify(s); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() with another size err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestNewSignerPanics(t *testing.T) {
+	for _, size := range []int{0, minSignatureLen - 1, maxSignatureLen + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewSigner() with size %d didn't panic", size)
+				}
+			}()
+			NewSigner(nil, WithSignatureSize(size))
+		}()
+	}
+}
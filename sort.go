@@ -0,0 +1,47 @@
+package xtoken
+
+import "sort"
+
+// Less reports whether token sorts before other. Because the raw layout is
+// time-prefixed, this is creation order.
+func (token Token) Less(other Token) bool {
+	return token.Compare(other) < 0
+}
+
+// Compare returns an integer comparing two tokens, it is a package-level
+// form of Token.Compare suitable for slices.SortFunc and slices.BinarySearchFunc.
+func Compare(a, b Token) int {
+	return a.Compare(b)
+}
+
+// Tokens attaches the methods of sort.Interface to []Token, sorting in
+// increasing order.
+type Tokens []Token
+
+func (t Tokens) Len() int           { return len(t) }
+func (t Tokens) Less(i, j int) bool { return t[i].Less(t[j]) }
+func (t Tokens) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+// Sort is a convenience method: t.Sort() calls sort.Sort(t).
+func (t Tokens) Sort() { sort.Sort(t) }
+
+// Sort sorts a slice of tokens in increasing order.
+func Sort(tokens []Token) {
+	sort.Sort(Tokens(tokens))
+}
+
+// Min returns the smaller of a and b.
+func Min(a, b Token) Token {
+	if b.Less(a) {
+		return b
+	}
+	return a
+}
+
+// Max returns the larger of a and b.
+func Max(a, b Token) Token {
+	if a.Less(b) {
+		return b
+	}
+	return a
+}
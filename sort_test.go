@@ -0,0 +1,77 @@
+package xtoken
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSort(t *testing.T) {
+	base := time.Now()
+	var tokens []Token
+	// Generate out of order timestamps, several tokens per second.
+	for _, offset := range []int{5, 0, 3, 1, 4, 2} {
+		for i := 0; i < 3; i++ {
+			tokens = append(tokens, NewWithTime(base.Add(time.Duration(offset)*time.Second)))
+		}
+	}
+
+	byTime := make([]Token, len(tokens))
+	copy(byTime, tokens)
+	sort.Slice(byTime, func(i, j int) bool {
+		ti, tj := byTime[i].Time(), byTime[j].Time()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return byTime[i].Counter() < byTime[j].Counter()
+	})
+
+	Sort(tokens)
+	for i := range tokens {
+		if tokens[i] != byTime[i] {
+			t.Fatalf("Sort() order differs from (Time, Counter) order at %d", i)
+		}
+		if i > 0 && !tokens[i-1].Less(tokens[i]) {
+			t.Errorf("tokens[%d].Less(tokens[%d]) = false, want true", i-1, i)
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	tokens := Tokens{IDs[0].token, IDs[2].token, IDs[1].token}
+	tokens.Sort()
+	if !sort.IsSorted(tokens) {
+		t.Errorf("Tokens.Sort() = %v, want sorted", tokens)
+	}
+	if tokens[0] != IDs[1].token || tokens[2] != IDs[0].token {
+		t.Errorf("Tokens.Sort() = %v, want [%v %v %v]", tokens, IDs[1].token, IDs[2].token, IDs[0].token)
+	}
+}
+
+func TestCompareMinMax(t *testing.T) {
+	a, b := IDs[1].token, IDs[0].token
+	if got := Compare(a, b); got != -1 {
+		t.Errorf("Compare(a, b) = %d, want -1", got)
+	}
+	if got := Compare(b, a); got != 1 {
+		t.Errorf("Compare(b, a) = %d, want 1", got)
+	}
+	if got := Compare(a, a); got != 0 {
+		t.Errorf("Compare(a, a) = %d, want 0", got)
+	}
+	if got := Min(a, b); got != a {
+		t.Errorf("Min() = %v, want %v", got, a)
+	}
+	if got := Min(b, a); got != a {
+		t.Errorf("Min() = %v, want %v", got, a)
+	}
+	if got := Max(a, b); got != b {
+		t.Errorf("Max() = %v, want %v", got, b)
+	}
+	if got := Max(b, a); got != b {
+		t.Errorf("Max() = %v, want %v", got, b)
+	}
+	if a.Less(a) {
+		t.Error("a.Less(a) = true, want false")
+	}
+}
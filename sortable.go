@@ -0,0 +1,81 @@
+package xtoken
+
+const (
+	sortableEncodedLen = 20 // sortable string encoded len
+
+	// sortableEncoding is the base32hex alphabet, its symbols are in ascending
+	// byte order so the lexicographic order of encoded strings is the byte order of tokens.
+	sortableEncoding = "0123456789abcdefghijklmnopqrstuv"
+)
+
+// sortableDec is the decoding map for sortableEncoding
+var sortableDec [256]byte
+
+func init() {
+	for i := 0; i < len(sortableDec); i++ {
+		sortableDec[i] = 0xFF
+	}
+	for i := 0; i < len(sortableEncoding); i++ {
+		sortableDec[sortableEncoding[i]] = byte(i)
+	}
+}
+
+// SortableString returns a 20 chars base32hex lowercased representation of the token
+// (char set is 0-9, a-v). Unlike String, symbols are at fixed positions so the
+// lexicographic order of the strings is the chronological order of the tokens.
+func (token Token) SortableString() string {
+	text := make([]byte, sortableEncodedLen)
+	encodeBase32(text, token[:], sortableEncoding)
+	return string(text)
+}
+
+// FromSortableString reads a token from its SortableString representation.
+func FromSortableString(s string) (Token, error) {
+	var token Token
+	if err := checkText(s, sortableEncodedLen, &sortableDec); err != nil {
+		return token, err
+	}
+	if !decodeBase32(token[:], s, &sortableDec) {
+		return nilToken, ErrInconsistentToken
+	}
+	return token, nil
+}
+
+// encodeBase32 packs the bits of src into 5-bit symbols of alphabet, big endian,
+// the last symbol is padded with zero bits. dst must be (len(src)*8+4)/5 long.
+func encodeBase32(dst, src []byte, alphabet string) {
+	var acc uint32
+	bits := 0
+	n := 0
+	for _, b := range src {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			dst[n] = alphabet[(acc>>uint(bits))&0x1F]
+			n++
+		}
+	}
+	if bits > 0 {
+		dst[n] = alphabet[(acc<<uint(5-bits))&0x1F]
+	}
+}
+
+// decodeBase32 is the inverse of encodeBase32 with the decoding map dec, src
+// must be (len(dst)*8+4)/5 long and only contain symbols of dec. It returns false
+// when the padding bits are not zero so that every value has a single valid encoding.
+func decodeBase32(dst []byte, src string, dec *[256]byte) bool {
+	var acc uint32
+	bits := 0
+	n := 0
+	for i := 0; i < len(src); i++ {
+		acc = acc<<5 | uint32(dec[src[i]])
+		bits += 5
+		if bits >= 8 && n < len(dst) {
+			bits -= 8
+			dst[n] = byte(acc >> uint(bits))
+			n++
+		}
+	}
+	return acc&(1<<uint(bits)-1) == 0
+}
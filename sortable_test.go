@@ -0,0 +1,73 @@
+package xtoken
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortableString(t *testing.T) {
+	for i, v := range IDs {
+		s := v.token.SortableString()
+		if len(s) != sortableEncodedLen {
+			t.Errorf("IDs[%d].SortableString() len = %d, want %d", i, len(s), sortableEncodedLen)
+		}
+		got, err := FromSortableString(s)
+		if err != nil {
+			t.Fatalf("FromSortableString(%q) err: %v", s, err)
+		}
+		if got != v.token {
+			t.Errorf("FromSortableString(%q) = %x, want %x", s, got[:], v.token[:])
+		}
+		// Both encodings decode to the same raw bytes.
+		fromString, err := FromString(v.token.String())
+		if err != nil {
+			t.Fatalf("FromString() err: %v", err)
+		}
+		if fromString != got {
+			t.Errorf("FromString() = %x, FromSortableString() = %x", fromString[:], got[:])
+		}
+	}
+	if got, want := IDs[0].token.SortableString(), "9m4e2mr0ui3e8a215n4g"; got != want {
+		t.Errorf("SortableString() = %q, want %q", got, want)
+	}
+}
+
+func TestSortableStringOrder(t *testing.T) {
+	tokens := []Token{nilToken, {0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}}
+	for i := 0; i < 1000; i++ {
+		var token Token
+		if _, err := rand.Read(token[:]); err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, token)
+	}
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		tokens = append(tokens, NewWithTime(now.Add(time.Duration(i)*time.Second)))
+	}
+	for _, a := range tokens {
+		for _, b := range tokens {
+			if got, want := strings.Compare(a.SortableString(), b.SortableString()), a.Compare(b); got != want {
+				t.Fatalf("strings.Compare(%q, %q) = %d, want %d", a.SortableString(), b.SortableString(), got, want)
+			}
+		}
+	}
+}
+
+func TestFromSortableStringInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"9m4e2mr0ui3e8a215n4",   // too short
+		"9m4e2mr0ui3e8a215n4g0", // too long
+		"9m4e2mr0ui3e8a215n4w",  // w is out of the alphabet
+		"9M4E2MR0UI3E8A215N4G",  // uppercase is not accepted
+		"9m4e2mr0ui3e8a215n4h",  // padding bits set
+	} {
+		if _, err := FromSortableString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromSortableString(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+}
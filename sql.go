@@ -0,0 +1,132 @@
+package xtoken
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// StorageFormat is the shape of a token in a database column.
+type StorageFormat int
+
+const (
+	// FormatString stores the 32 chars string representation, e.g. in a CHAR(32) column.
+	FormatString StorageFormat = iota
+	// FormatHex stores the 24 chars hex representation, e.g. in a CHAR(24) column.
+	FormatHex
+	// FormatBinary stores the 12 raw bytes, e.g. in a BINARY(12) column.
+	FormatBinary
+)
+
+// String returns the name of the format.
+func (f StorageFormat) String() string {
+	switch f {
+	case FormatString:
+		return "string"
+	case FormatHex:
+		return "hex"
+	case FormatBinary:
+		return "binary"
+	}
+	return fmt.Sprintf("StorageFormat(%d)", int(f))
+}
+
+// Value implements driver.Valuer, the token is stored as its string
+// representation, see ValueAs for the other formats. The nil token is stored
+// as NULL.
+func (token Token) Value() (driver.Value, error) {
+	return token.ValueAs(FormatString)
+}
+
+// ValueAs returns the driver.Value of the token in format f, the nil token is
+// stored as NULL.
+func (token Token) ValueAs(f StorageFormat) (driver.Value, error) {
+	if token.IsZero() {
+		return nil, nil
+	}
+	switch f {
+	case FormatString:
+		return token.String(), nil
+	case FormatHex:
+		return token.Hex(), nil
+	case FormatBinary:
+		return token[:], nil
+	}
+	return nil, fmt.Errorf("xtoken: unknown storage format %v", f)
+}
+
+// Scan implements sql.Scanner, it accepts the values of all the storage
+// formats: strings and byte slices of the string or hex representations, and
+// byte slices of the 12 raw bytes. NULL is the nil token.
+func (token *Token) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*token = nilToken
+		return nil
+	case string, []byte:
+		t, err := ParseAny(src)
+		if err != nil {
+			return fmt.Errorf("xtoken: scanning %T: %w", src, err)
+		}
+		*token = t
+		return nil
+	}
+	return fmt.Errorf("xtoken: scanning unsupported type %T", src)
+}
+
+// BinaryToken is a Token stored as its 12 raw bytes, e.g. in a BINARY(12)
+// column. It scans all the storage formats like Token.
+type BinaryToken Token
+
+// Value implements driver.Valuer.
+func (token BinaryToken) Value() (driver.Value, error) {
+	return Token(token).ValueAs(FormatBinary)
+}
+
+// Scan implements sql.Scanner.
+func (token *BinaryToken) Scan(src interface{}) error {
+	return (*Token)(token).Scan(src)
+}
+
+// HexToken is a Token stored as its 24 chars hex representation, e.g. in a
+// CHAR(24) column. It scans all the storage formats like Token.
+type HexToken Token
+
+// Value implements driver.Valuer.
+func (token HexToken) Value() (driver.Value, error) {
+	return Token(token).ValueAs(FormatHex)
+}
+
+// Scan implements sql.Scanner.
+func (token *HexToken) Scan(src interface{}) error {
+	return (*Token)(token).Scan(src)
+}
+
+// NullToken is a Token that may be NULL, like sql.NullString. Unlike Token,
+// it keeps the nil token apart from NULL in a nullable column.
+type NullToken struct {
+	Token Token
+	Valid bool // Valid is true if Token is not NULL
+}
+
+// Value implements driver.Valuer, the token is stored as its string
+// representation.
+func (n NullToken) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Token.String(), nil
+}
+
+// Scan implements sql.Scanner, it accepts the same values as Token.Scan.
+func (n *NullToken) Scan(src interface{}) error {
+	if src == nil {
+		n.Token, n.Valid = nilToken, false
+		return nil
+	}
+	if err := n.Token.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,130 @@
+package xtoken
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Token{}
+	_ sql.Scanner   = (*Token)(nil)
+	_ driver.Valuer = BinaryToken{}
+	_ sql.Scanner   = (*BinaryToken)(nil)
+	_ driver.Valuer = HexToken{}
+	_ sql.Scanner   = (*HexToken)(nil)
+	_ driver.Valuer = NullToken{}
+	_ sql.Scanner   = (*NullToken)(nil)
+)
+
+func TestValueAs(t *testing.T) {
+	token := New()
+	tests := []struct {
+		format StorageFormat
+		want   driver.Value
+	}{
+		{FormatHex, token.Hex()},
+		{FormatBinary, token[:]},
+	}
+	for _, tt := range tests {
+		got, err := token.ValueAs(tt.format)
+		if err != nil {
+			t.Fatalf("ValueAs(%v) err: %v", tt.format, err)
+		}
+		if b, ok := tt.want.([]byte); ok {
+			if !bytes.Equal(got.([]byte), b) {
+				t.Errorf("ValueAs(%v) = %v, want %v", tt.format, got, tt.want)
+			}
+		} else if got != tt.want {
+			t.Errorf("ValueAs(%v) = %v, want %v", tt.format, got, tt.want)
+		}
+	}
+	if v, err := token.Value(); err != nil || MustFromString(v.(string)) != token {
+		t.Errorf("Value() = %v, %v, want the string representation of %v", v, err, token)
+	}
+	if v, err := nilToken.Value(); err != nil || v != nil {
+		t.Errorf("nilToken.Value() = %v, %v, want nil", v, err)
+	}
+	if _, err := token.ValueAs(StorageFormat(42)); err == nil {
+		t.Error("ValueAs(42) err = nil, want an error")
+	}
+}
+
+func TestScan(t *testing.T) {
+	token := New()
+	for _, src := range []interface{}{
+		token.String(),
+		[]byte(token.String()),
+		token.Hex(),
+		[]byte(token.Hex()),
+		token[:],
+	} {
+		var got Token
+		if err := got.Scan(src); err != nil || got != token {
+			t.Errorf("Scan(%v) = %v, %v, want %v", src, got, err, token)
+		}
+		var bin BinaryToken
+		if err := bin.Scan(src); err != nil || Token(bin) != token {
+			t.Errorf("BinaryToken.Scan(%v) = %v, %v, want %v", src, bin, err, token)
+		}
+	}
+	got := token
+	if err := got.Scan(nil); err != nil || !got.IsZero() {
+		t.Errorf("Scan(nil) = %v, %v, want the nil token", got, err)
+	}
+	for _, src := range []interface{}{"bad", []byte{1, 2, 3}, 42} {
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%v) err = nil, want an error", src)
+		}
+	}
+	if err := got.Scan("bad"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Scan() err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestWrapperValues(t *testing.T) {
+	token := New()
+	if v, err := BinaryToken(token).Value(); err != nil || !bytes.Equal(v.([]byte), token[:]) {
+		t.Errorf("BinaryToken.Value() = %v, %v, want %v", v, err, token[:])
+	}
+	if v, err := HexToken(token).Value(); err != nil || v != token.Hex() {
+		t.Errorf("HexToken.Value() = %v, %v, want %v", v, err, token.Hex())
+	}
+	var hex HexToken
+	if err := hex.Scan(token.Hex()); err != nil || Token(hex) != token {
+		t.Errorf("HexToken.Scan() = %v, %v, want %v", hex, err, token)
+	}
+}
+
+func TestNullToken(t *testing.T) {
+	v, err := NullToken{}.Value()
+	if err != nil || v != nil {
+		t.Errorf("NullToken{}.Value() = %v, %v, want nil, nil", v, err)
+	}
+	v, err = NullToken{Valid: true}.Value()
+	if s, ok := v.(string); err != nil || !ok || MustFromString(s) != nilToken {
+		t.Errorf("NullToken{Valid: true}.Value() = %v, %v, want the nil token", v, err)
+	}
+
+	token := New()
+	for _, src := range []interface{}{token.String(), []byte(token.Hex()), token[:]} {
+		var n NullToken
+		if err := n.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) err: %v", src, err)
+		}
+		if !n.Valid || n.Token != token {
+			t.Errorf("Scan(%v) = %+v, want %v", src, n, token)
+		}
+	}
+
+	n := NullToken{Token: token, Valid: true}
+	if err := n.Scan(nil); err != nil || n.Valid || !n.Token.IsZero() {
+		t.Errorf("Scan(nil) = %+v, %v, want NULL", n, err)
+	}
+	n = NullToken{Token: token, Valid: true}
+	if err := n.Scan("invalid"); !errors.Is(err, ErrInvalidToken) || n.Valid {
+		t.Errorf("Scan(\"invalid\") = %+v, %v, want ErrInvalidToken", n, err)
+	}
+}
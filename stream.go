@@ -0,0 +1,149 @@
+package xtoken
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// StreamFormat is the layout of the tokens of an Encoder or Decoder stream.
+type StreamFormat int
+
+const (
+	// StreamLines is one String representation per line, lines end with '\n'.
+	// The Decoder also accepts "\r\n" and skips blank lines.
+	StreamLines StreamFormat = iota
+	// StreamRaw is the 12 raw bytes of each token, one after the other. The
+	// records have a fixed size, so there is no delimiter.
+	StreamRaw
+)
+
+// Encoder writes a sequence of tokens to an io.Writer, without allocating per
+// token. Its output is buffered, call Flush when done.
+type Encoder struct {
+	w      *bufio.Writer
+	format StreamFormat
+	buf    [encodedLen + 1]byte
+}
+
+// NewEncoder returns an Encoder writing tokens to w in format.
+func NewEncoder(w io.Writer, format StreamFormat) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w), format: format}
+}
+
+// Encode writes token.
+func (e *Encoder) Encode(token Token) error {
+	// Writing from e.buf keeps token on the stack.
+	if e.format == StreamRaw {
+		copy(e.buf[:], token[:])
+		_, err := e.w.Write(e.buf[:rawLen])
+		return err
+	}
+	StdEncoding.encode(e.buf[:encodedLen], token[:])
+	e.buf[encodedLen] = '\n'
+	_, err := e.w.Write(e.buf[:])
+	return err
+}
+
+// EncodeAll writes tokens.
+func (e *Encoder) EncodeAll(tokens []Token) error {
+	for _, token := range tokens {
+		if err := e.Encode(token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the buffered tokens to the underlying io.Writer.
+func (e *Encoder) Flush() error {
+	return e.w.Flush()
+}
+
+// Decoder reads a sequence of tokens from an io.Reader, without allocating per
+// token.
+type Decoder struct {
+	r      *bufio.Reader
+	format StreamFormat
+	index  int
+	buf    [rawLen]byte
+}
+
+// NewDecoder returns a Decoder reading tokens from r in format.
+func NewDecoder(r io.Reader, format StreamFormat) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), format: format}
+}
+
+// Decode reads the next token, it returns io.EOF at the end of the input. A
+// malformed token returns a *ParseError holding its index in the stream, the
+// Decoder then moves on to the next one. A truncated raw token returns
+// io.ErrUnexpectedEOF.
+func (d *Decoder) Decode() (Token, error) {
+	var token Token
+	if d.format == StreamRaw {
+		if _, err := io.ReadFull(d.r, d.buf[:]); err != nil {
+			return token, err
+		}
+		d.index++
+		return Token(d.buf), nil
+	}
+	for {
+		line, n, err := d.readLine()
+		if n == 0 {
+			if err != nil {
+				return token, err
+			}
+			continue
+		}
+		i := d.index
+		d.index++
+		if n != len(line) {
+			return token, &ParseError{Index: i, Err: &InvalidLengthError{Got: n, Want: encodedLen}}
+		}
+		if err := StdEncoding.decodeText(&token, line); err != nil {
+			return token, &ParseError{Index: i, Err: err}
+		}
+		return token, nil
+	}
+}
+
+// readLine returns the next line without its line ending and its length, the
+// error is only set when there is no line left. A line longer than the buffer
+// is skipped, only its length is returned.
+func (d *Decoder) readLine() ([]byte, int, error) {
+	line, err := d.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		n := len(line)
+		for err == bufio.ErrBufferFull {
+			line, err = d.r.ReadSlice('\n')
+			n += len(line)
+		}
+		if err == nil {
+			n-- // the '\n'
+		}
+		return nil, n, nil
+	}
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	return line, len(line), err
+}
+
+// DecodeAll reads tokens until tokens is full or the input ends, and returns
+// the number read. It stops at the first error, which is io.EOF when the input
+// ends before any token is read.
+func (d *Decoder) DecodeAll(tokens []Token) (int, error) {
+	for n := range tokens {
+		token, err := d.Decode()
+		if err != nil {
+			if err == io.EOF && n > 0 {
+				err = nil
+			}
+			return n, err
+		}
+		tokens[n] = token
+	}
+	return len(tokens), nil
+}
@@ -0,0 +1,130 @@
+package xtoken
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	tokens := NewBatch(1000)
+	tokens[10] = nilToken
+	for _, format := range []StreamFormat{StreamLines, StreamRaw} {
+		var buf bytes.Buffer
+		e := NewEncoder(&buf, format)
+		if err := e.EncodeAll(tokens); err != nil {
+			t.Fatalf("EncodeAll() err: %v", err)
+		}
+		if err := e.Flush(); err != nil {
+			t.Fatalf("Flush() err: %v", err)
+		}
+		want := len(tokens) * rawLen
+		if format == StreamLines {
+			want = len(tokens) * (encodedLen + 1)
+		}
+		if buf.Len() != want {
+			t.Errorf("format %d: %d bytes written, want %d", format, buf.Len(), want)
+		}
+
+		d := NewDecoder(&buf, format)
+		got := make([]Token, len(tokens)+1)
+		n, err := d.DecodeAll(got)
+		if err != nil || n != len(tokens) {
+			t.Fatalf("format %d: DecodeAll() = %d, %v, want %d", format, n, err, len(tokens))
+		}
+		for i := range tokens {
+			if got[i] != tokens[i] {
+				t.Fatalf("format %d: token %d = %v, want %v", format, i, got[i], tokens[i])
+			}
+		}
+		if _, err := d.Decode(); err != io.EOF {
+			t.Errorf("format %d: Decode() at the end err = %v, want io.EOF", format, err)
+		}
+		if n, err := d.DecodeAll(got); n != 0 || err != io.EOF {
+			t.Errorf("format %d: DecodeAll() at the end = %d, %v, want 0, io.EOF", format, n, err)
+		}
+	}
+}
+
+func TestDecoderLines(t *testing.T) {
+	a, b := IDs[0].token, IDs[1].token
+	in := "\n" + a.String() + "\r\n\n" + "garbage\n" + strings.Repeat("x", 5000) + "\n" + b.String()
+	d := NewDecoder(strings.NewReader(in), StreamLines)
+	if got, err := d.Decode(); err != nil || got != a {
+		t.Fatalf("Decode() = %v, %v, want %v", got, err, a)
+	}
+	for _, want := range []struct {
+		index, got int
+	}{{1, 7}, {2, 5000}} {
+		_, err := d.Decode()
+		var parseErr *ParseError
+		var lengthErr *InvalidLengthError
+		if !errors.As(err, &parseErr) || parseErr.Index != want.index || !errors.As(err, &lengthErr) || lengthErr.Got != want.got {
+			t.Errorf("Decode() err = %v, want a length error of %d chars at index %d", err, want.got, want.index)
+		}
+	}
+	// The Decoder goes on after a malformed token, the last line has no '\n'.
+	if got, err := d.Decode(); err != nil || got != b {
+		t.Fatalf("Decode() = %v, %v, want %v", got, err, b)
+	}
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Decode() at the end err = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderRawTruncated(t *testing.T) {
+	d := NewDecoder(bytes.NewReader(make([]byte, rawLen+5)), StreamRaw)
+	if _, err := d.Decode(); err != nil {
+		t.Fatalf("Decode() err: %v", err)
+	}
+	if _, err := d.Decode(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Decode() of 5 bytes err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestStreamAllocs(t *testing.T) {
+	tokens := NewBatch(100)
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, StreamLines)
+	e.EncodeAll(tokens)
+	e.Flush()
+	data := buf.Bytes()
+	got := make([]Token, len(tokens))
+	allocs := testing.AllocsPerRun(10, func() {
+		buf.Reset()
+		e.EncodeAll(tokens)
+		e.Flush()
+		d := NewDecoder(bytes.NewReader(data), StreamLines)
+		d.DecodeAll(got)
+	})
+	// The Decoder and its buffer, not one per token.
+	if allocs > 5 {
+		t.Errorf("%v allocs for %d tokens, want a constant number", allocs, len(tokens))
+	}
+}
+
+func BenchmarkEncoderLines(b *testing.B) {
+	token := New()
+	e := NewEncoder(io.Discard, StreamLines)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.Encode(token)
+	}
+	e.Flush()
+}
+
+func BenchmarkDecoderLines(b *testing.B) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, StreamLines)
+	e.EncodeAll(NewBatch(1000))
+	e.Flush()
+	data := buf.Bytes()
+	got := make([]Token, 1000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		NewDecoder(bytes.NewReader(data), StreamLines).DecodeAll(got)
+	}
+}
@@ -0,0 +1,64 @@
+package xtoken
+
+import (
+	"time"
+)
+
+const (
+	// ErrTimeInFuture is returned by ParseStrict when the timestamp of a token
+	// is further in the future than the allowed clock skew.
+	ErrTimeInFuture strErr = "Token timestamp is in the future"
+
+	// ErrTimeTooOld is returned by ParseStrict when the timestamp of a token is
+	// before the allowed range.
+	ErrTimeTooOld strErr = "Token timestamp is too old"
+)
+
+// parsePolicy is the set of checks ParseStrict applies to the timestamp.
+type parsePolicy struct {
+	maxSkew   time.Duration
+	notBefore time.Time
+	hasSkew   bool
+}
+
+// ParseOption configures ParseStrict.
+type ParseOption func(p *parsePolicy)
+
+// MaxClockSkew rejects tokens with a timestamp more than d after the current time.
+func MaxClockSkew(d time.Duration) ParseOption {
+	return func(p *parsePolicy) {
+		p.maxSkew = d
+		p.hasSkew = true
+	}
+}
+
+// NotBefore rejects tokens with a timestamp before t, which includes the nil
+// token for any t after the Unix epoch.
+func NotBefore(t time.Time) ParseOption {
+	return func(p *parsePolicy) {
+		p.notBefore = t
+	}
+}
+
+// ParseStrict is like FromString but also checks the timestamp of the token
+// against opts, tokens that are well-formed but out of policy are rejected with
+// ErrTimeInFuture or ErrTimeTooOld instead of an error wrapping ErrInvalidToken.
+// Bounds are inclusive and it assumes the default Unix epoch.
+func ParseStrict(s string, opts ...ParseOption) (Token, error) {
+	token, err := FromString(s)
+	if err != nil {
+		return token, err
+	}
+	var p parsePolicy
+	for _, opt := range opts {
+		opt(&p)
+	}
+	t := token.Time()
+	if p.hasSkew && t.After(timeNow().Add(p.maxSkew)) {
+		return nilToken, ErrTimeInFuture
+	}
+	if t.Before(p.notBefore) {
+		return nilToken, ErrTimeTooOld
+	}
+	return token, nil
+}
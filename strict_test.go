@@ -0,0 +1,61 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseStrict(t *testing.T) {
+	now := time.Unix(IDs[0].timestamp, 0)
+	withNow(t, now)
+	at := func(d time.Duration) string {
+		return NewWithTime(now.Add(d)).String()
+	}
+	skew := MaxClockSkew(time.Minute)
+	notBefore := NotBefore(now.Add(-time.Hour))
+	tests := []struct {
+		name string
+		s    string
+		opts []ParseOption
+		err  error
+	}{
+		{"no policy", at(100 * 365 * 24 * time.Hour), nil, nil},
+		{"no policy nil token", nilToken.String(), nil, nil},
+		{"now", at(0), []ParseOption{skew, notBefore}, nil},
+		{"at the skew limit", at(time.Minute), []ParseOption{skew}, nil},
+		{"past the skew limit", at(time.Minute + time.Second), []ParseOption{skew}, ErrTimeInFuture},
+		{"no skew allowed", at(time.Second), []ParseOption{MaxClockSkew(0)}, ErrTimeInFuture},
+		{"at not before", at(-time.Hour), []ParseOption{notBefore}, nil},
+		{"before not before", at(-time.Hour - time.Second), []ParseOption{notBefore}, ErrTimeTooOld},
+		{"nil token with skew", nilToken.String(), []ParseOption{skew}, nil},
+		{"nil token with not before", nilToken.String(), []ParseOption{skew, notBefore}, ErrTimeTooOld},
+		{"malformed", "bad input", []ParseOption{skew, notBefore}, ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := ParseStrict(tt.s, tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseStrict(%q) err = %v, want %v", tt.s, err, tt.err)
+			}
+			if err != nil {
+				if !token.IsZero() {
+					t.Errorf("ParseStrict(%q) = %v, want the nil token", tt.s, token)
+				}
+				return
+			}
+			if want := MustFromString(tt.s); token != want {
+				t.Errorf("ParseStrict(%q) = %v, want %v", tt.s, token, want)
+			}
+		})
+	}
+}
+
+func TestParseStrictErrors(t *testing.T) {
+	// Out of policy tokens are well-formed.
+	for _, err := range []error{ErrTimeInFuture, ErrTimeTooOld} {
+		if errors.Is(err, ErrInvalidToken) {
+			t.Errorf("errors.Is(%v, ErrInvalidToken) = true, want false", err)
+		}
+	}
+}
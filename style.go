@@ -0,0 +1,161 @@
+package xtoken
+
+import (
+	"fmt"
+)
+
+// Style is a string representation of a token, for EncodeTo and DecodeFrom.
+type Style int
+
+const (
+	// StyleString is the String representation, with shuffled symbols.
+	StyleString Style = iota
+	// StyleCanonical is the CanonicalString representation, a single string
+	// per token which FromString reads as well.
+	StyleCanonical
+	// StyleHex is the 24 chars Hex representation.
+	StyleHex
+	// StyleBase32 is the 20 chars unpadded RFC 4648 base32 representation
+	// (char set is A-Z and 2-7), lowercase is accepted as well.
+	StyleBase32
+	// StyleBase58 is the 17 chars base58 representation with the Bitcoin
+	// alphabet, left padded with its zero symbol '1'.
+	StyleBase58
+	// StyleBase64URL is the 16 chars Base64 representation, with the
+	// base64.RawURLEncoding alphabet.
+	StyleBase64URL
+)
+
+const (
+	base32EncodedLen = 20 // RFC 4648 base32 encoded len
+	base58EncodedLen = 17 // base58 encoded len
+
+	// base32Encoding is the RFC 4648 base32 alphabet.
+	base32Encoding = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+	// base58Encoding is the Bitcoin base58 alphabet, without 0, O, I and l.
+	base58Encoding = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// base32Dec is the decoding map for base32Encoding, it folds lowercase, and
+// base58Dec the one for base58Encoding.
+var base32Dec, base58Dec [256]byte
+
+func init() {
+	for i := 0; i < 256; i++ {
+		base32Dec[i] = 0xFF
+		base58Dec[i] = 0xFF
+	}
+	for i := 0; i < len(base32Encoding); i++ {
+		c := base32Encoding[i]
+		base32Dec[c] = byte(i)
+		if c >= 'A' && c <= 'Z' {
+			base32Dec[c-'A'+'a'] = byte(i)
+		}
+	}
+	for i := 0; i < len(base58Encoding); i++ {
+		base58Dec[base58Encoding[i]] = byte(i)
+	}
+}
+
+// String returns the name of the style.
+func (s Style) String() string {
+	switch s {
+	case StyleString:
+		return "string"
+	case StyleCanonical:
+		return "canonical"
+	case StyleHex:
+		return "hex"
+	case StyleBase32:
+		return "base32"
+	case StyleBase58:
+		return "base58"
+	case StyleBase64URL:
+		return "base64url"
+	}
+	return fmt.Sprintf("Style(%d)", int(s))
+}
+
+// EncodeTo returns the representation of the token in style, it panics if
+// style isn't one of the Style constants.
+func (token Token) EncodeTo(style Style) string {
+	switch style {
+	case StyleString:
+		return token.String()
+	case StyleCanonical:
+		return token.CanonicalString()
+	case StyleHex:
+		return token.Hex()
+	case StyleBase32:
+		text := make([]byte, base32EncodedLen)
+		encodeBase32(text, token[:], base32Encoding)
+		return string(text)
+	case StyleBase58:
+		return token.base58()
+	case StyleBase64URL:
+		return token.Base64()
+	}
+	panic(fmt.Sprintf("xtoken: unknown style %v", style))
+}
+
+// DecodeFrom reads a Token from its representation in style. Malformed input
+// returns an error wrapping ErrInvalidToken, as does an unknown style.
+func DecodeFrom(s string, style Style) (Token, error) {
+	switch style {
+	case StyleString, StyleCanonical:
+		return FromString(s)
+	case StyleHex:
+		return FromHex(s)
+	case StyleBase32:
+		var token Token
+		if err := checkText(s, base32EncodedLen, &base32Dec); err != nil {
+			return token, err
+		}
+		if !decodeBase32(token[:], s, &base32Dec) {
+			return nilToken, ErrInconsistentToken
+		}
+		return token, nil
+	case StyleBase58:
+		return fromBase58(s)
+	case StyleBase64URL:
+		return FromBase64(s)
+	}
+	return nilToken, fmt.Errorf("%w: unknown style %v", ErrInvalidToken, style)
+}
+
+// base58 returns the base58 representation of the token, see StyleBase58.
+func (token Token) base58() string {
+	var text [base58EncodedLen]byte
+	for i := len(text) - 1; i >= 0; i-- {
+		// Divide the big endian number token by 58, the remainder is the next symbol.
+		var rem uint32
+		for j := range token {
+			v := rem<<8 | uint32(token[j])
+			token[j], rem = byte(v/58), v%58
+		}
+		text[i] = base58Encoding[rem]
+	}
+	return string(text[:])
+}
+
+// fromBase58 reads a Token from its base58 representation, values which
+// don't fit in 12 bytes are rejected with ErrInconsistentToken.
+func fromBase58(s string) (Token, error) {
+	var token Token
+	if err := checkText(s, base58EncodedLen, &base58Dec); err != nil {
+		return token, err
+	}
+	for i := 0; i < len(s); i++ {
+		// Multiply the big endian number token by 58 and add the symbol.
+		carry := uint32(base58Dec[s[i]])
+		for j := len(token) - 1; j >= 0; j-- {
+			v := uint32(token[j])*58 + carry
+			token[j], carry = byte(v), v>>8
+		}
+		if carry != 0 {
+			return nilToken, ErrInconsistentToken
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,97 @@
+package xtoken
+
+import (
+	"encoding/base32"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var styles = []Style{StyleString, StyleCanonical, StyleHex, StyleBase32, StyleBase58, StyleBase64URL}
+
+func TestEncodeToRoundTrip(t *testing.T) {
+	tokens := []Token{nilToken, {0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}}
+	for _, v := range IDs {
+		tokens = append(tokens, v.token)
+	}
+	for _, style := range styles {
+		for _, token := range tokens {
+			s := token.EncodeTo(style)
+			got, err := DecodeFrom(s, style)
+			if err != nil || got != token {
+				t.Errorf("DecodeFrom(%q, %v) = %x, %v, want %x", s, style, got[:], err, token[:])
+			}
+		}
+	}
+}
+
+func TestEncodeToStandard(t *testing.T) {
+	for _, v := range IDs {
+		token := v.token
+		if got, want := token.EncodeTo(StyleBase32), base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(token[:]); got != want {
+			t.Errorf("EncodeTo(StyleBase32) = %q, want %q", got, want)
+		}
+		want := new(big.Int).SetBytes(token[:]).Text(58)
+		// math/big uses 0-9a-zA-V, map it to the Bitcoin alphabet.
+		const bigDigits = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUV"
+		var b strings.Builder
+		for i := len(want); i < base58EncodedLen; i++ {
+			b.WriteByte(base58Encoding[0])
+		}
+		for i := 0; i < len(want); i++ {
+			b.WriteByte(base58Encoding[strings.IndexByte(bigDigits, want[i])])
+		}
+		if got := token.EncodeTo(StyleBase58); got != b.String() {
+			t.Errorf("EncodeTo(StyleBase58) = %q, want %q", got, b.String())
+		}
+	}
+	if got, want := nilToken.EncodeTo(StyleBase58), strings.Repeat("1", base58EncodedLen); got != want {
+		t.Errorf("nil EncodeTo(StyleBase58) = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeFromErrors(t *testing.T) {
+	token := IDs[0].token
+	if got, err := DecodeFrom(strings.ToLower(token.EncodeTo(StyleBase32)), StyleBase32); err != nil || got != token {
+		t.Errorf("DecodeFrom() of lowercase base32 = %x, %v, want %x", got[:], err, token[:])
+	}
+	tests := []struct {
+		name  string
+		s     string
+		style Style
+		err   error
+	}{
+		{"base58 overflow", strings.Repeat("z", base58EncodedLen), StyleBase58, ErrInconsistentToken},
+		{"base58 look-alike", "0" + strings.Repeat("1", base58EncodedLen-1), StyleBase58, ErrBadChar},
+		{"base32 length", "AAAA", StyleBase32, ErrBadLength},
+		{"base32 padding bits", strings.Repeat("A", base32EncodedLen-1) + "B", StyleBase32, ErrInconsistentToken},
+		{"hex as base64url", token.Hex(), StyleBase64URL, ErrBadLength},
+		{"unknown style", token.String(), Style(42), ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeFrom(tt.s, tt.style); !errors.Is(err, tt.err) {
+				t.Errorf("DecodeFrom(%q, %v) err = %v, want %v", tt.s, tt.style, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEncodeToUnknownStyle(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("EncodeTo(Style(42)) didn't panic")
+		}
+	}()
+	IDs[0].token.EncodeTo(Style(42))
+}
+
+func TestStyleString(t *testing.T) {
+	if got := StyleBase64URL.String(); got != "base64url" {
+		t.Errorf("String() = %q, want %q", got, "base64url")
+	}
+	if got := Style(42).String(); got != "Style(42)" {
+		t.Errorf("String() = %q, want %q", got, "Style(42)")
+	}
+}
@@ -0,0 +1,37 @@
+package xtoken
+
+// tagIndex is the position of the tag in the raw token, it replaces the last
+// byte of the machine id.
+const tagIndex = 6
+
+// NewTagged generates a globally unique Token carrying tag, see Generator.NewTagged.
+func NewTagged(tag byte) Token {
+	return defaultGenerator.NewTagged(tag)
+}
+
+// NewTagged generates a globally unique Token carrying tag, which can be read
+// back with Token.Tag, e.g. to tell apart tokens of different namespaces.
+//
+// The tag takes the place of the last byte of the machine id rather than the
+// high byte of the counter, so tagged and untagged tokens keep 1<<24 tokens
+// per second, at the cost of a 2-byte machine id: two hosts are more likely
+// to share it, and then only their pid keeps their tokens apart.
+// New generates tokens with tag 0, tokens are unique across tags since they
+// share the counter. RandomTag is reserved for NewRandom, NewTagged panics
+// with ErrReservedTag for it.
+func (g *Generator) NewTagged(tag byte) Token {
+	if tag == RandomTag {
+		panic(ErrReservedTag)
+	}
+	token := g.New()
+	token[tagIndex] = tag
+	return token
+}
+
+// Tag returns the tag of a token generated by NewTagged, 0 for New and
+// RandomTag for NewRandom.
+// Tokens generated by older versions of the package carry a byte of their
+// machine id instead.
+func (token Token) Tag() byte {
+	return token[tagIndex]
+}
@@ -0,0 +1,49 @@
+package xtoken
+
+import (
+	"testing"
+)
+
+func TestNewTagged(t *testing.T) {
+	for _, tag := range []byte{0, 1, 0x7F, 0xFE} {
+		token := NewTagged(tag)
+		if got := token.Tag(); got != tag {
+			t.Errorf("Tag() = %d, want %d", got, tag)
+		}
+		got, err := FromString(token.String())
+		if err != nil {
+			t.Fatalf("FromString() err: %v", err)
+		}
+		if got.Tag() != tag || got != token {
+			t.Errorf("FromString() = %x, want %x", got[:], token[:])
+		}
+		if got, err := FromCompactString(token.CompactString()); err != nil || got.Tag() != tag {
+			t.Errorf("FromCompactString() = %x, %v, want tag %d", got[:], err, tag)
+		}
+	}
+	if got := New().Tag(); got != 0 {
+		t.Errorf("New().Tag() = %d, want 0", got)
+	}
+}
+
+func TestNewTaggedRandomTag(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrReservedTag {
+			t.Errorf("NewTagged(RandomTag) panicked with %v, want %v", r, ErrReservedTag)
+		}
+	}()
+	NewTagged(RandomTag)
+}
+
+func TestNewTaggedUniqueness(t *testing.T) {
+	const n = 10000
+	seen := make(map[Token]struct{}, 3*n)
+	for i := 0; i < n; i++ {
+		for _, token := range []Token{New(), NewTagged(1), NewTagged(2)} {
+			if _, ok := seen[token]; ok {
+				t.Fatalf("duplicate token %x with tag %d", token[:], token.Tag())
+			}
+			seen[token] = struct{}{}
+		}
+	}
+}
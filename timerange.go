@@ -0,0 +1,105 @@
+package xtoken
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// MinTokenForTime returns the smallest possible token for the second of t: the
+// timestamp is set and the remaining 8 bytes are zero. Together with
+// MaxTokenForTime it allows time-range queries on raw token keys.
+// Times before the Unix epoch clamp to zero and times past the uint32 horizon
+// clamp to the largest timestamp instead of wrapping.
+func MinTokenForTime(t time.Time) Token {
+	return minTokenForSeconds(clampSeconds(t, 0))
+}
+
+// MaxTokenForTime returns the largest possible token for the second of t: the
+// timestamp is set and the remaining 8 bytes are 0xFF.
+// It clamps out of range times the same way as MinTokenForTime.
+func MaxTokenForTime(t time.Time) Token {
+	return maxTokenForSeconds(clampSeconds(t, 0))
+}
+
+// InTimeRange reports whether the timestamp of the token is between from and to,
+// both inclusive, with a resolution of one second.
+func (token Token) InTimeRange(from, to time.Time) bool {
+	return token.Compare(MinTokenForTime(from)) >= 0 && token.Compare(MaxTokenForTime(to)) <= 0
+}
+
+// MinTokenMilliForTime is like MinTokenForTime for TokenMilli, with a
+// resolution of one millisecond. Times before the Unix epoch clamp to zero and
+// times past the 6-byte horizon clamp to the largest timestamp.
+func MinTokenMilliForTime(t time.Time) TokenMilli {
+	var token TokenMilli
+	ms := clampMillis(t, 1<<48-1)
+	token[0] = byte(ms >> 40)
+	token[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(token[2:], uint32(ms))
+	return token
+}
+
+// MaxTokenMilliForTime is like MaxTokenForTime for TokenMilli, see
+// MinTokenMilliForTime.
+func MaxTokenMilliForTime(t time.Time) TokenMilli {
+	token := MinTokenMilliForTime(t)
+	for i := 6; i < milliRawLen; i++ {
+		token[i] = 0xFF
+	}
+	return token
+}
+
+// MinTokenWideForTime is like MinTokenForTime for TokenWide, with a resolution
+// of one millisecond. Times before the Unix epoch clamp to zero.
+func MinTokenWideForTime(t time.Time) TokenWide {
+	var token TokenWide
+	binary.BigEndian.PutUint64(token[:], clampMillis(t, math.MaxInt64))
+	return token
+}
+
+// MaxTokenWideForTime is like MaxTokenForTime for TokenWide, see
+// MinTokenWideForTime.
+func MaxTokenWideForTime(t time.Time) TokenWide {
+	token := MinTokenWideForTime(t)
+	for i := 8; i < wideRawLen; i++ {
+		token[i] = 0xFF
+	}
+	return token
+}
+
+func minTokenForSeconds(secs uint32) Token {
+	var token Token
+	binary.BigEndian.PutUint32(token[:], secs)
+	return token
+}
+
+func maxTokenForSeconds(secs uint32) Token {
+	token := minTokenForSeconds(secs)
+	for i := 4; i < rawLen; i++ {
+		token[i] = 0xFF
+	}
+	return token
+}
+
+// clampSeconds returns the seconds of t since epoch clamped to the uint32 range.
+func clampSeconds(t time.Time, epoch int64) uint32 {
+	secs := t.Unix() - epoch
+	if secs < 0 {
+		return 0
+	}
+	if secs > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(secs)
+}
+
+// clampMillis returns the milliseconds of t since the Unix epoch clamped to
+// [0, limit].
+func clampMillis(t time.Time, limit uint64) uint64 {
+	ms := t.UnixMilli()
+	if ms < 0 {
+		return 0
+	}
+	return min(uint64(ms), limit)
+}
@@ -0,0 +1,121 @@
+package xtoken
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMinMaxTokenForTime(t *testing.T) {
+	ts := time.Unix(1300816219, 0)
+	min, max := MinTokenForTime(ts), MaxTokenForTime(ts)
+	if got, want := min, (Token{0x4d, 0x88, 0xe1, 0x5b}); got != want {
+		t.Errorf("MinTokenForTime() = %x, want %x", got[:], want[:])
+	}
+	if got, want := max, (Token{0x4d, 0x88, 0xe1, 0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}); got != want {
+		t.Errorf("MaxTokenForTime() = %x, want %x", got[:], want[:])
+	}
+	if !min.Time().Equal(ts) || !max.Time().Equal(ts) {
+		t.Errorf("Time() = %v, %v, want %v", min.Time(), max.Time(), ts)
+	}
+	if !IDs[0].token.InTimeRange(ts, ts) {
+		t.Errorf("InTimeRange() = false, want true")
+	}
+}
+
+func TestMinMaxTokenForTimeClamp(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want uint32
+	}{
+		{"before epoch", time.Unix(-1, 0), 0},
+		{"zero time", time.Time{}, 0},
+		{"epoch", time.Unix(0, 0), 0},
+		{"horizon", time.Unix(math.MaxUint32, 0), math.MaxUint32},
+		{"past horizon", time.Unix(math.MaxUint32+1, 0), math.MaxUint32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uint32(MinTokenForTime(tt.time).Time().Unix()); got != tt.want {
+				t.Errorf("MinTokenForTime() secs = %d, want %d", got, tt.want)
+			}
+			if got := uint32(MaxTokenForTime(tt.time).Time().Unix()); got != tt.want {
+				t.Errorf("MaxTokenForTime() secs = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInTimeRange(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	var tokens []Token
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 5; j++ {
+			tokens = append(tokens, NewWithTime(base.Add(time.Duration(i)*time.Second)))
+		}
+	}
+	from, to := base.Add(5*time.Second), base.Add(12*time.Second)
+	min, max := MinTokenForTime(from), MaxTokenForTime(to)
+	matched := 0
+	for _, token := range tokens {
+		between := token.Compare(min) >= 0 && token.Compare(max) <= 0
+		byTime := !token.Time().Before(from) && !token.Time().After(to)
+		if between != byTime {
+			t.Errorf("BETWEEN = %v, Time() filter = %v for %v", between, byTime, token.Time())
+		}
+		if got := token.InTimeRange(from, to); got != byTime {
+			t.Errorf("InTimeRange() = %v, want %v", got, byTime)
+		}
+		if between {
+			matched++
+		}
+	}
+	if matched != 8*5 {
+		t.Errorf("matched %d tokens, want %d", matched, 8*5)
+	}
+}
+
+func TestMinMaxTokenMilliForTime(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+	min, max := MinTokenMilliForTime(ts), MaxTokenMilliForTime(ts)
+	if !min.Time().Equal(ts) || !max.Time().Equal(ts) {
+		t.Errorf("Time() = %v, %v, want %v", min.Time(), max.Time(), ts)
+	}
+	if min.Machine()[0] != 0 || max.Counter() != maxCounter {
+		t.Errorf("MinTokenMilliForTime() = %x, MaxTokenMilliForTime() = %x", min[:], max[:])
+	}
+	token := NewMilliWithTime(ts)
+	if bytes.Compare(min[:], token[:]) > 0 || bytes.Compare(token[:], max[:]) > 0 {
+		t.Errorf("%x not in [%x, %x]", token[:], min[:], max[:])
+	}
+	next := NewMilliWithTime(ts.Add(time.Millisecond))
+	if bytes.Compare(next[:], max[:]) <= 0 {
+		t.Errorf("%x of the next millisecond <= %x", next[:], max[:])
+	}
+	if got := MinTokenMilliForTime(time.Unix(-1, 0)); got != nilTokenMilli {
+		t.Errorf("MinTokenMilliForTime() before the epoch = %x, want zero", got[:])
+	}
+	if got := MinTokenMilliForTime(time.UnixMilli(1 << 48)).UnixMilli(); got != 1<<48-1 {
+		t.Errorf("MinTokenMilliForTime() past the horizon ms = %d, want %d", got, int64(1<<48-1))
+	}
+}
+
+func TestMinMaxTokenWideForTime(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+	min, max := MinTokenWideForTime(ts), MaxTokenWideForTime(ts)
+	if !min.Time().Equal(ts) || !max.Time().Equal(ts) {
+		t.Errorf("Time() = %v, %v, want %v", min.Time(), max.Time(), ts)
+	}
+	token, _ := NewWideWithTime(ts)
+	if min.Compare(token) > 0 || token.Compare(max) > 0 {
+		t.Errorf("%x not in [%x, %x]", token[:], min[:], max[:])
+	}
+	if next, _ := NewWideWithTime(ts.Add(time.Millisecond)); next.Compare(max) <= 0 {
+		t.Errorf("%x of the next millisecond <= %x", next[:], max[:])
+	}
+	if got := MinTokenWideForTime(time.Unix(-1, 0)); !got.IsZero() {
+		t.Errorf("MinTokenWideForTime() before the epoch = %x, want zero", got[:])
+	}
+}
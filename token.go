@@ -2,13 +2,13 @@ package xtoken
 
 import (
 	"bytes"
-	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/binary"
 	"fmt"
 	"hash/crc32"
-	mathRand "math/rand"
 	"os"
+	"runtime"
 	"sync/atomic"
 	"time"
 )
@@ -21,6 +21,27 @@ func (err strErr) Error() string { return string(err) }
 const (
 	// ErrInvalidToken is returned when trying to unmarshal an invalid Token.
 	ErrInvalidToken strErr = "invalid Token"
+
+	// ErrTimeOutOfRange is returned when a time can't be stored in the 4-byte timestamp.
+	ErrTimeOutOfRange strErr = "time out of range of Token"
+
+	// ErrCounterOverflow is returned when the 3-byte counter would wrap within one second.
+	ErrCounterOverflow strErr = "Token counter overflow"
+
+	// ErrCounterOutOfRange is returned when a counter value can't be stored in 3 bytes.
+	ErrCounterOutOfRange strErr = "counter out of range of Token"
+
+	// ErrRateLimited is returned by NewStrict when the Generator issued its
+	// maximum number of tokens in the current second, see WithMaxPerSecond.
+	ErrRateLimited strErr = "Token rate limited"
+
+	// ErrClockStalled is returned by NewE when it has to wait for the next
+	// second but the clock of the Generator doesn't advance, see WithClock.
+	ErrClockStalled strErr = "Token clock stalled"
+
+	// ErrReservedTag is the panic value of NewTagged for RandomTag, which
+	// tells apart the tokens of NewRandom.
+	ErrReservedTag strErr = "Token tag reserved"
 )
 
 type Token [rawLen]byte
@@ -36,105 +57,203 @@ const (
 var (
 	// objectIDCounter is atomically incremented when generating a new ObjectId. It's
 	// used as the counter part of an id. This id is initialized with a random value.
-	objectIDCounter = randInt()
+	objectIDCounter = mustRandInt()
+
+	// counterGeneration is incremented by Reinitialize when it reseeds
+	// objectIDCounter, so that Generators drop their counter window.
+	counterGeneration uint32
 
-	// machineID is generated once and used in subsequent calls to the New* functions.
-	machineID = readMachineID()
+	// machineID is generated once and used in subsequent calls to the New* functions,
+	// Reinitialize replaces it. Token stores its first 2 bytes, TokenMilli 3 and
+	// TokenWide all 4. It's accessed atomically.
+	machineID atomic.Pointer[[4]byte]
 
-	// pid stores the current process id
-	pid = os.Getpid()
+	// pid stores the 2 low bytes of the pid embedded in new tokens, the process
+	// id mixed with the cpuset unless overridden by SetPid or DisablePidMixing.
+	// It's accessed atomically.
+	pid atomic.Uint32
 
 	nilToken Token
 
-	// dec is the decoding map for base32 encoding
-	dec [256]byte
+	// valuePositions are the positions of the value bytes in the encoded form.
+	valuePositions = [...]int{0, 3, 5, 7, 9, 11, 17, 19, 21, 23, 27, 31}
+
+	// orderPositions are the positions of the order bytes in the encoded form.
+	orderPositions = [...]int{2, 13, 22, 30, 6, 15, 26, 10, 18, 1, 14, 25}
 )
 
 func init() {
-	for i := 0; i < len(dec); i++ {
-		dec[i] = 0xFF
-	}
-	for i := 0; i < len(encoding); i++ {
-		dec[encoding[i]] = byte(i)
-	}
+	machineID.Store((*[4]byte)(mustReadMachineID()))
+	pid.Store(uint32(uint16(mixedPid())))
+}
 
+// mixedPid returns the current process id, mixed with the cpuset in containers.
+func mixedPid() int {
+	p := os.Getpid()
 	// If /proc/self/cpuset exists and is not /, we can assume that we are in a
 	// form of container and use the content of cpuset xor-ed with the PID in
-	// order get a reasonable machine global unique PID.
-	b, err := os.ReadFile("/proc/self/cpuset")
-	if err == nil && len(b) > 1 {
-		pid ^= int(crc32.ChecksumIEEE(b))
+	// order get a reasonable machine global unique PID. cpuset only exists on
+	// Linux, so don't bother touching the filesystem anywhere else.
+	if runtime.GOOS == "linux" {
+		b, err := os.ReadFile("/proc/self/cpuset")
+		if err == nil && len(b) > 1 {
+			p ^= int(crc32.ChecksumIEEE(b))
+		}
 	}
+	return p
 }
 
-// readMachineID generates a machine ID, derived from a platform-specific machine ID
-// value, or else the machine's hostname, or else a randomly-generated number.
-// It panics if all of these methods fail.
-func readMachineID() []byte {
-	id := make([]byte, 3)
-	hid, err := readPlatformMachineID()
+// MachineIDEnv is the environment variable which, when set, overrides the
+// platform sources of the machine id, e.g. with the UID of a Kubernetes pod.
+const MachineIDEnv = "XTOKEN_MACHINE_ID"
+
+// readMachineID generates a machine ID, derived from the MachineIDEnv variable,
+// or else a platform-specific machine ID value, or else the machine's hostname,
+// or else a randomly-generated number.
+// It fails if all of these methods fail.
+func readMachineID() ([]byte, error) {
+	id := make([]byte, 4)
+	hid, err := os.Getenv(MachineIDEnv), error(nil)
+	if hid == "" {
+		hid, err = readPlatformMachineID()
+	}
 	if err != nil || len(hid) == 0 {
 		hid, err = os.Hostname()
 	}
 	if err == nil && len(hid) != 0 {
-		hw := sha256.New()
-		hw.Write([]byte(hid))
-		copy(id, hw.Sum(nil))
+		h := hashMachineID([]byte(hid))
+		copy(id, h[:])
 	} else {
 		// Fallback to rand number if machine id can't be gathered
-		if _, randErr := rand.Reader.Read(id); randErr != nil {
-			panic(fmt.Errorf("xtoken: cannot get hostname nor generate a random number: %v; %v", err, randErr))
+		if randErr := readRand(id); randErr != nil {
+			return nil, fmt.Errorf("xtoken: cannot get hostname nor generate a random number: %v; %w", err, randErr)
 		}
 	}
-	return id
+	return id, nil
 }
 
-// randInt generates a random uint32
-func randInt() uint32 {
-	b := make([]byte, 3)
-	if _, err := rand.Reader.Read(b); err != nil {
-		panic(fmt.Errorf("xtoken: cannot generate random number: %v", err))
+// hashMachineID derives a machine id from an identity of any length.
+func hashMachineID(b []byte) *[4]byte {
+	sum := sha256.Sum256(b)
+	return (*[4]byte)(sum[:4])
+}
+
+// mustReadMachineID is like readMachineID but panics on failure, it's only used
+// when the package is loaded.
+func mustReadMachineID() []byte {
+	id, err := readMachineID()
+	if err != nil {
+		panic(err)
 	}
-	return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	return id
 }
 
 // New generates a globally unique Token
 func New() Token {
-	return NewWithTime(time.Now())
+	return defaultGenerator.New()
 }
 
-// NewWithTime generates a globally unique Token with the passed in time
+// NewE generates a globally unique Token, see Generator.NewE.
+func NewE() (Token, error) {
+	return defaultGenerator.NewE()
+}
+
+// NewString generates a globally unique Token and returns its string
+// representation, see Generator.NewString.
+func NewString() string {
+	return defaultGenerator.NewString()
+}
+
+// NewStrict generates a globally unique Token, it returns ErrCounterOverflow
+// instead of waiting for the next second when the counter would wrap.
+func NewStrict() (Token, error) {
+	return defaultGenerator.NewStrict()
+}
+
+// NewWithTime generates a globally unique Token with the passed in time.
+// Times outside of the range of Token.Time silently wrap, use NewWithTimeE to
+// get an error instead.
 func NewWithTime(t time.Time) Token {
+	return defaultGenerator.NewWithTime(t)
+}
+
+// NewWithTimeE is like NewWithTime but returns a *TimeOutOfRangeError, which
+// satisfies errors.Is(err, ErrTimeOutOfRange), when t is out of the range of
+// Token.Time, which includes the zero time.Time.
+func NewWithTimeE(t time.Time) (Token, error) {
+	return defaultGenerator.NewWithTimeE(t)
+}
+
+// newToken assembles a Token from the passed in timestamp and counter value, the
+// machine id and the pid.
+func (g *Generator) newToken(secs, i uint32) Token {
 	var token Token
 	// Timestamp, 4 bytes, big endian
-	binary.BigEndian.PutUint32(token[:], uint32(t.Unix()))
-	// Machine ID, 3 bytes
-	token[4] = machineID[0]
-	token[5] = machineID[1]
-	token[6] = machineID[2]
+	binary.BigEndian.PutUint32(token[:], secs)
+	// Machine ID, 2 bytes, the third one is the tag, 0 for untagged tokens.
+	m := g.machine
+	if m == nil {
+		m = machineID.Load()
+	}
+	token[4] = m[0]
+	token[5] = m[1]
 	// Pid, 2 bytes, specs don't specify endianness, but we use big endian.
-	token[7] = byte(pid >> 8)
-	token[8] = byte(pid)
+	p := uint16(pid.Load())
+	if g.hasPid {
+		p = g.pid
+	}
+	token[7] = byte(p >> 8)
+	token[8] = byte(p)
 	// Increment, 3 bytes, big endian
-	i := atomic.AddUint32(&objectIDCounter, 1)
 	token[9] = byte(i >> 16)
 	token[10] = byte(i >> 8)
 	token[11] = byte(i)
+	if g.pepper != nil {
+		return g.pepper.Obfuscate(token)
+	}
 	return token
 }
 
-// Time returns the timestamp part of the token.
+// nextCounter advances the counter by step and reports whether the returned
+// value is still unique for the timestamp secs, that is the counter advanced by
+// less than 1<<24 since the first value the Generator used in that second.
+func (g *Generator) nextCounter(secs, step uint32) (uint32, bool) {
+	i := atomic.AddUint32(g.counter, step)
+	if gen := atomic.LoadUint32(&counterGeneration); atomic.LoadUint32(&g.generation) != gen {
+		// The window refers to the previous seed of the counter.
+		atomic.StoreUint32(&g.generation, gen)
+		atomic.StoreUint64(&g.window, 0)
+	}
+	for {
+		w := atomic.LoadUint64(&g.window)
+		if uint32(w>>32) == secs {
+			// A concurrent caller may have opened the window with a later value,
+			// hence the signed difference.
+			return i, int32(i-uint32(w)) < 1<<24
+		}
+		// The window opens at the first value of the reserved range, not the
+		// last one, or a large reservation would shift it forward.
+		if atomic.CompareAndSwapUint64(&g.window, w, uint64(secs)<<32|uint64(i-step+1)) {
+			return i, true
+		}
+	}
+}
+
+// Time returns the timestamp part of the token, it assumes the default Unix epoch,
+// use Generator.Time for tokens generated with a custom epoch. The timestamp
+// is an unsigned 32-bit number of seconds, so it ranges from 1970-01-01T00:00:00Z
+// to 2106-02-07T06:28:15Z.
 // It's a runtime error to call this method with an invalid token.
 func (token Token) Time() time.Time {
-	// First 4 bytes of ObjectId is 32-bit big-endian seconds from epoch.
-	secs := int64(binary.BigEndian.Uint32(token[0:4]))
-	return time.Unix(secs, 0)
+	return time.Unix(token.Unix(), 0)
 }
 
-// Machine returns the 3-byte machine id part of the token.
+// Machine returns a copy of the 3-byte machine id part of the token, New and
+// NewTagged store the tag of the token in its last byte, see Token.Tag.
 // It's a runtime error to call this method with an invalid token.
 func (token Token) Machine() []byte {
-	return token[4:7]
+	m := token.MachineID()
+	return m[:]
 }
 
 // Pid returns the process id part of the token.
@@ -151,18 +270,80 @@ func (token Token) Counter() int32 {
 	return int32(uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]))
 }
 
-// FromString reads an ID from its string representation
+// FromString reads an ID from its string representation, the 20 chars of a
+// CompactString are accepted as well.
 func FromString(token string) (Token, error) {
+	if len(token) == compactEncodedLen {
+		return FromCompactString(token)
+	}
 	i := &Token{}
 	err := i.UnmarshalText([]byte(token))
 	return *i, err
 }
 
+// MustFromString is like FromString but panics if the token cannot be parsed,
+// it simplifies the initialization of fixtures and global variables.
+func MustFromString(token string) Token {
+	t, err := FromString(token)
+	if err != nil {
+		panic(fmt.Errorf("xtoken: cannot parse %q: %w", token, err))
+	}
+	return t
+}
+
+// FromStringOrNil is like FromString but returns the nil token if the token
+// cannot be parsed.
+func FromStringOrNil(token string) Token {
+	t, err := FromString(token)
+	if err != nil {
+		return nilToken
+	}
+	return t
+}
+
 // String returns a base32 hex lowercased with no padding representation of the id (char set is 0-9, a-v).
 func (token Token) String() string {
-	text := make([]byte, encodedLen)
-	encode(text, token[:])
-	return string(text)
+	return StdEncoding.EncodeToString(token)
+}
+
+// MarshalText implements encoding.TextMarshaler, the token is serialized as its
+// string representation, which UnmarshalText reads back.
+func (token Token) MarshalText() ([]byte, error) {
+	return token.AppendText(make([]byte, 0, encodedLen))
+}
+
+// AppendText implements encoding.TextAppender, it appends the string
+// representation of the token to b.
+func (token Token) AppendText(b []byte) ([]byte, error) {
+	return StdEncoding.appendEncode(b, token), nil
+}
+
+// AppendString appends the string representation of the token to b, like
+// AppendText without the error, it doesn't allocate when b has room for 32 chars.
+func (token Token) AppendString(b []byte) []byte {
+	return StdEncoding.appendEncode(b, token)
+}
+
+// AppendBinary implements encoding.BinaryAppender, it appends the 12 raw bytes
+// of the token to b.
+func (token Token) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, token[:]...), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, the token is serialized
+// as its 12 raw bytes.
+func (token Token) MarshalBinary() ([]byte, error) {
+	return token.AppendBinary(make([]byte, 0, rawLen))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, data must hold the 12
+// raw bytes of a token.
+func (token *Token) UnmarshalBinary(data []byte) error {
+	if len(data) != rawLen {
+		return &InvalidLengthError{Got: len(data), Want: rawLen}
+	}
+	copy(token[:], data)
+	return nil
 }
 
 // IsZero Returns true if this is a "nil" ID
@@ -182,107 +363,27 @@ func (token Token) Compare(other Token) int {
 	return bytes.Compare(token[:], other[:])
 }
 
-// encode by unrolling the stdlib base32 algorithm + removing all safe checks
-// value: 0,3,5,7,9,11,17,19,21,23,27,31
-// padding: 4,8,12,16,20,24,28,29
-// objectIDCounter order: 1,14,25
-// time order: 2,13,22,30
-// machine id order: 6,15,26
-// pid order: 10,18
-func encode(dst, token []byte) {
-	_ = dst[encodedLen-1]
-	_ = token[rawLen-1]
-	orderIdxs := []int{0, 3, 5, 7, 9, 11, 17, 19, 21, 23, 27, 31}
-	mathRand.Shuffle(len(orderIdxs), func(i, j int) {
-		orderIdxs[i], orderIdxs[j] = orderIdxs[j], orderIdxs[i]
-	})
-
-	// order: 12 bytes
-	// time order: 2, 13 ,22 ,30
-	dst[2] = encoding[orderIdxs[0]]
-	dst[13] = encoding[orderIdxs[1]]
-	dst[22] = encoding[orderIdxs[2]]
-	dst[30] = encoding[orderIdxs[3]]
-	// machine id order
-	dst[6] = encoding[orderIdxs[4]]
-	dst[15] = encoding[orderIdxs[5]]
-	dst[26] = encoding[orderIdxs[6]]
-	// pid order
-	dst[10] = encoding[orderIdxs[7]]
-	dst[18] = encoding[orderIdxs[8]]
-	// objectIDCounter order: 1, 14 ,25
-	dst[1] = encoding[orderIdxs[9]]
-	dst[14] = encoding[orderIdxs[10]]
-	dst[25] = encoding[orderIdxs[11]]
-
-	// set value and padding
-	dst[orderIdxs[0]] = encoding[(token[0]>>3)&encodingIdxMax]
-	dst[orderIdxs[1]] = encoding[(token[1]>>6)|(token[0]<<2)&encodingIdxMax]
-	dst[orderIdxs[2]] = encoding[(token[1]>>1)&encodingIdxMax]
-	dst[orderIdxs[3]] = encoding[(token[2]>>4)|(token[1]<<4)&encodingIdxMax]
-	dst[4] = encoding[token[3]>>7|(token[2]<<1)&encodingIdxMax]
-	dst[8] = encoding[(token[3]>>2)&encodingIdxMax]
-	dst[orderIdxs[4]] = encoding[token[4]>>5|(token[3]<<3)&encodingIdxMax]
-	dst[orderIdxs[5]] = encoding[token[4]&encodingIdxMax]
-	dst[orderIdxs[6]] = encoding[token[5]>>3]
-	dst[12] = encoding[(token[6]>>6)|(token[5]<<2)&encodingIdxMax]
-	dst[16] = encoding[(token[6]>>1)&encodingIdxMax]
-	dst[orderIdxs[7]] = encoding[(token[7]>>4)|(token[6]<<4)&encodingIdxMax]
-	dst[orderIdxs[8]] = encoding[token[8]>>7|(token[7]<<1)&encodingIdxMax]
-	dst[20] = encoding[(token[8]>>2)&encodingIdxMax]
-	dst[orderIdxs[9]] = encoding[(token[9]>>5)|(token[8]<<3)&encodingIdxMax]
-	dst[24] = encoding[token[9]&encodingIdxMax]
-	dst[orderIdxs[10]] = encoding[token[10]>>3]
-	dst[28] = encoding[(token[11]>>6)|(token[10]<<2)&encodingIdxMax]
-	dst[orderIdxs[11]] = encoding[(token[11]>>1)&encodingIdxMax]
-	dst[29] = encoding[(token[11]<<4)&encodingIdxMax]
+// EqualConstantTime reports whether two tokens are equal, in constant time.
+// Use it instead of == or Compare when the token is a secret, such as a session
+// or password reset token.
+func (token Token) EqualConstantTime(other Token) bool {
+	return subtle.ConstantTimeCompare(token[:], other[:]) == 1
 }
 
-// UnmarshalText implements encoding.TextUnmarshaler.
-func (token *Token) UnmarshalText(text []byte) error {
-	if len(text) != encodedLen {
-		return ErrInvalidToken
-	}
-	for _, c := range text {
-		if dec[c] == 0xFF {
-			return ErrInvalidToken
-		}
-	}
-	if !decode(token, text) {
-		*token = nilToken
-		return ErrInvalidToken
+// SecureCompareString decodes a and b and reports whether they represent the
+// same token, comparing the raw bytes in constant time. Since the encoding is
+// shuffled, two different strings may represent the same token.
+// It returns false if either string is malformed.
+func SecureCompareString(a, b string) bool {
+	ta, errA := FromString(a)
+	tb, errB := FromString(b)
+	if errA != nil || errB != nil {
+		return false
 	}
-	return nil
+	return ta.EqualConstantTime(tb)
 }
 
-// decode by unrolling the stdlib base32 algorithm + customized safe check.
-// 19: 29, 18: dec[src[25]], 17: 28, 16: dec[src[14]], 15: 24
-// 14: dec[src[1]], 13: 20, 12: dec[src[18]], 11: dec[src[10]]
-// 10: 16, 9: 12, 8: dec[src[26]], 7: dec[src[15]], 6: dec[src[6]]
-// 5: 8, 4: 4, 3: dec[src[30]], 2: dec[src[22]], 1: dec[src[13]], 0: dec[src[2]]
-func decode(token *Token, src []byte) bool {
-	_ = src[encodedLen-1]
-	_ = token[rawLen-1]
-
-	token[11] = dec[src[28]]<<6 | dec[src[dec[src[25]]]]<<1 | dec[src[29]]>>4
-	// check the last byte
-	if encoding[(token[11]<<4)&encodingIdxMax] != src[29] {
-		return false
-	}
-	token[10] = dec[src[dec[src[14]]]]<<3 | dec[src[28]]>>2
-	token[9] = dec[src[dec[src[1]]]]<<5 | dec[src[24]]
-
-	token[8] = dec[src[dec[src[18]]]]<<7 | dec[src[20]]<<2 | dec[src[dec[src[1]]]]>>3
-	token[7] = dec[src[dec[src[10]]]]<<4 | dec[src[dec[src[18]]]]>>1
-
-	token[6] = dec[src[12]]<<6 | dec[src[16]]<<1 | dec[src[dec[src[10]]]]>>4
-	token[5] = dec[src[dec[src[26]]]]<<3 | dec[src[12]]>>2
-	token[4] = dec[src[dec[src[6]]]]<<5 | dec[src[dec[src[15]]]]
-	//
-	token[3] = dec[src[4]]<<7 | dec[src[8]]<<2 | dec[src[dec[src[6]]]]>>3
-	token[2] = dec[src[dec[src[30]]]]<<4 | dec[src[4]]>>1
-	token[1] = dec[src[dec[src[13]]]]<<6 | dec[src[dec[src[22]]]]<<1 | dec[src[dec[src[30]]]]>>4
-	token[0] = dec[src[dec[src[2]]]]<<3 | dec[src[dec[src[13]]]]>>2
-
-	return true
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (token *Token) UnmarshalText(text []byte) error {
+	return StdEncoding.decodeText(token, text)
 }
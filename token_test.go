@@ -2,7 +2,13 @@ package xtoken
 
 import (
 	"bytes"
+	"encoding/gob"
+	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -72,6 +78,17 @@ func TestIDPartsExtraction(t *testing.T) {
 	}
 }
 
+func TestNewString(t *testing.T) {
+	a, b := New(), MustFromString(NewString())
+	if got, want := int(b.Counter()-a.Counter()), 1; got != want {
+		t.Errorf("NewString() counter delta = %d, want %d", got, want)
+	}
+	g := NewGenerator(WithCounter(41), WithPid(7))
+	if got := MustFromString(g.NewString()); got.Counter() != 42 || got.Pid() != 7 {
+		t.Errorf("Generator.NewString() = %x, want counter 42 and pid 7", got[:])
+	}
+}
+
 func TestNew(t *testing.T) {
 	// Generate 10 tokens
 	tokens := make([]Token, 10000)
@@ -109,6 +126,262 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewWithTimeE(t *testing.T) {
+	first := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2106, 2, 7, 6, 28, 15, 0, time.UTC)
+	tests := []struct {
+		name string
+		time time.Time
+		err  error
+	}{
+		{"first second", first, nil},
+		{"last second", last, nil},
+		{"before first second", first.Add(-time.Second), ErrTimeOutOfRange},
+		{"past last second", last.Add(time.Second), ErrTimeOutOfRange},
+		{"zero time", time.Time{}, ErrTimeOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := NewWithTimeE(tt.time)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NewWithTimeE() err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				var rangeErr *TimeOutOfRangeError
+				if !errors.As(err, &rangeErr) || !rangeErr.Time.Equal(tt.time) ||
+					!rangeErr.Min.Equal(first) || !rangeErr.Max.Equal(last) {
+					t.Errorf("NewWithTimeE() err = %#v, want a *TimeOutOfRangeError", err)
+				}
+				if !token.IsZero() {
+					t.Errorf("NewWithTimeE() = %v, want the nil token", token)
+				}
+				return
+			}
+			if got := token.Time(); !got.Equal(tt.time) {
+				t.Errorf("Time() = %v, want %v", got, tt.time)
+			}
+		})
+	}
+}
+
+func TestNewWithTimeEErrorMessage(t *testing.T) {
+	_, err := NewWithTimeE(time.Time{})
+	want := "time 0001-01-01T00:00:00Z out of range of Token [1970-01-01T00:00:00Z, 2106-02-07T06:28:15Z]"
+	if err == nil || err.Error() != want {
+		t.Errorf("NewWithTimeE() err = %v, want %q", err, want)
+	}
+}
+
+func TestFromStringOrderOutOfRange(t *testing.T) {
+	// '_' decodes to 63, past the end of the encoded form.
+	if _, err := FromString("________________________________"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("FromString() err = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestEqualConstantTime(t *testing.T) {
+	a, b := New(), New()
+	if !a.EqualConstantTime(a) {
+		t.Error("a.EqualConstantTime(a) = false, want true")
+	}
+	if a.EqualConstantTime(b) {
+		t.Error("a.EqualConstantTime(b) = true, want false")
+	}
+	if !nilToken.EqualConstantTime(Token{}) {
+		t.Error("nilToken.EqualConstantTime(Token{}) = false, want true")
+	}
+}
+
+func TestSecureCompareString(t *testing.T) {
+	a, b := New(), New()
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a.String(), a.String(), true}, // two different encodings of a
+		{a.String(), b.String(), false},
+		{a.String(), "invalid", false},
+		{"invalid", "invalid", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := SecureCompareString(tt.a, tt.b); got != tt.want {
+			t.Errorf("SecureCompareString(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMustFromString(t *testing.T) {
+	token := New()
+	if got := MustFromString(token.String()); got != token {
+		t.Errorf("MustFromString() = %v, want %v", got, token)
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok {
+			t.Fatalf("MustFromString() panic value is not an error")
+		}
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("MustFromString() panic = %v, want it to wrap %v", err, ErrInvalidToken)
+		}
+		if !strings.Contains(err.Error(), `"bad input"`) {
+			t.Errorf("MustFromString() panic = %q, want the input", err.Error())
+		}
+	}()
+	MustFromString("bad input")
+	t.Error("MustFromString() didn't panic")
+}
+
+func TestFromStringOrNil(t *testing.T) {
+	token := New()
+	if got := FromStringOrNil(token.String()); got != token {
+		t.Errorf("FromStringOrNil() = %v, want %v", got, token)
+	}
+	for _, s := range []string{"", "bad input", "________________________________"} {
+		if got := FromStringOrNil(s); !got.IsZero() {
+			t.Errorf("FromStringOrNil(%q) = %v, want the nil token", s, got)
+		}
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	for _, v := range IDs {
+		text, err := v.token.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText() err: %v", err)
+		}
+		var got Token
+		if err := got.UnmarshalText(text); err != nil || got != v.token {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, v.token)
+		}
+	}
+}
+
+func TestTextVar(t *testing.T) {
+	want := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var got Token
+	fs.TextVar(&got, "token", nilToken, "token")
+	if err := fs.Parse([]string{"-token", want.String()}); err != nil {
+		t.Fatalf("Parse() err: %v", err)
+	}
+	if got != want {
+		t.Errorf("-token = %v, want %v", got, want)
+	}
+}
+
+func TestXML(t *testing.T) {
+	type session struct {
+		ID Token `xml:"id,attr"`
+	}
+	in := session{ID: New()}
+	b, err := xml.Marshal(in)
+	if err != nil {
+		t.Fatalf("xml.Marshal() err: %v", err)
+	}
+	var out session
+	if err := xml.Unmarshal(b, &out); err != nil || out != in {
+		t.Errorf("xml.Unmarshal(%s) = %v, %v, want %v", b, out, err, in)
+	}
+}
+
+func TestAppendText(t *testing.T) {
+	token := New()
+	for _, prefix := range [][]byte{nil, {}, []byte("token="), append(make([]byte, 0, 64), "token="...)} {
+		b, err := token.AppendText(prefix)
+		if err != nil {
+			t.Fatalf("AppendText() err: %v", err)
+		}
+		if len(b) != len(prefix)+encodedLen || string(b[:len(prefix)]) != string(prefix) {
+			t.Fatalf("AppendText(%q) = %q, want the prefix followed by %d chars", prefix, b, encodedLen)
+		}
+		got, err := FromString(string(b[len(prefix):]))
+		if err != nil || got != token {
+			t.Errorf("FromString(%q) = %v, %v, want %v", b[len(prefix):], got, err, token)
+		}
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	token := New()
+	b := token.AppendString([]byte("token="))
+	if got, err := FromString(strings.TrimPrefix(string(b), "token=")); err != nil || got != token {
+		t.Errorf("AppendString() = %q, want token= followed by %v", b, token)
+	}
+}
+
+func TestAppendBinary(t *testing.T) {
+	token := New()
+	for _, prefix := range [][]byte{nil, {}, {1, 2, 3}, append(make([]byte, 0, 64), 1, 2, 3)} {
+		b, err := token.AppendBinary(prefix)
+		if err != nil {
+			t.Fatalf("AppendBinary() err: %v", err)
+		}
+		want := append(append([]byte{}, prefix...), token[:]...)
+		if !bytes.Equal(b, want) {
+			t.Errorf("AppendBinary(%v) = %v, want %v", prefix, b, want)
+		}
+		// The result doesn't alias the token.
+		b[len(b)-1]++
+		if token.Bytes()[rawLen-1] == b[len(b)-1] {
+			t.Error("AppendBinary() aliases the token")
+		}
+	}
+}
+
+func TestBinaryMarshaler(t *testing.T) {
+	token := New()
+	b, err := token.MarshalBinary()
+	if err != nil || !bytes.Equal(b, token[:]) {
+		t.Fatalf("MarshalBinary() = %v, %v, want %v", b, err, token[:])
+	}
+	var got Token
+	if err := got.UnmarshalBinary(b); err != nil || got != token {
+		t.Errorf("UnmarshalBinary(%v) = %v, %v, want %v", b, got, err, token)
+	}
+	for _, data := range [][]byte{nil, b[1:], append(b, 0)} {
+		if err := got.UnmarshalBinary(data); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("UnmarshalBinary(%v) err = %v, want ErrInvalidToken", data, err)
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	type session struct {
+		ID Token
+	}
+	in := session{ID: New()}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode() err: %v", err)
+	}
+	var out session
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode() err: %v", err)
+	}
+	if out != in {
+		t.Errorf("Decode() = %v, want %v", out, in)
+	}
+}
+
+func TestAppendAllocs(t *testing.T) {
+	token := New()
+	buf := make([]byte, 0, 64)
+	if n := testing.AllocsPerRun(100, func() { _, _ = token.AppendText(buf[:0]) }); n != 0 {
+		t.Errorf("AppendText() allocs = %v, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = token.AppendString(buf[:0]) }); n != 0 {
+		t.Errorf("AppendString() allocs = %v, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _, _ = token.AppendBinary(buf[:0]) }); n != 0 {
+		t.Errorf("AppendBinary() allocs = %v, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _, _ = token.AppendText(nil) }); n != 1 {
+		t.Errorf("AppendText(nil) allocs = %v, want 1", n)
+	}
+}
+
 func BenchmarkNew(b *testing.B) {
 	b.ReportAllocs()
 	b.RunParallel(func(pb *testing.PB) {
@@ -118,11 +391,60 @@ func BenchmarkNew(b *testing.B) {
 	})
 }
 
+// stringSink keeps the benchmarked strings alive, or the compiler drops the
+// conversions of the unused ones.
+var stringSink atomic.Pointer[string]
+
 func BenchmarkNewString(b *testing.B) {
 	b.ReportAllocs()
 	b.RunParallel(func(pb *testing.PB) {
+		var s string
+		for pb.Next() {
+			s = NewString()
+		}
+		stringSink.Store(&s)
+	})
+}
+
+func BenchmarkNewStringWrapper(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var s string
 		for pb.Next() {
-			_ = New().String()
+			s = New().String()
 		}
+		stringSink.Store(&s)
 	})
 }
+
+func BenchmarkAppendString(b *testing.B) {
+	token := New()
+	buf := make([]byte, 0, encodedLen)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = token.AppendString(buf[:0])
+	}
+}
+
+func BenchmarkAppendText(b *testing.B) {
+	token := New()
+	buf := make([]byte, 0, encodedLen)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = token.AppendText(buf[:0])
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	x, y := New(), New()
+	for i := 0; i < b.N; i++ {
+		_ = x.Compare(y)
+	}
+}
+
+func BenchmarkEqualConstantTime(b *testing.B) {
+	x, y := New(), New()
+	for i := 0; i < b.N; i++ {
+		_ = x.EqualConstantTime(y)
+	}
+}
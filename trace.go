@@ -0,0 +1,58 @@
+package xtoken
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// traceDigest returns the SHA-256 of the raw token, the trace padding and the
+// span id are derived from it.
+func traceDigest(token Token) [sha256.Size]byte {
+	return sha256.Sum256(token[:])
+}
+
+// TraceID returns an OpenTelemetry trace id made of the 12 raw bytes of the
+// token followed by 4 bytes derived from a hash of them, so that logs and
+// traces correlate on the token. It's deterministic and never all zeros, even
+// for the nil token. FromTraceID recovers the token.
+func (token Token) TraceID() [16]byte {
+	var id [16]byte
+	copy(id[:], token[:])
+	binary.BigEndian.PutUint32(id[rawLen:], tracePadding(token))
+	return id
+}
+
+// SpanID returns an OpenTelemetry span id derived from a hash of the token,
+// it's deterministic and never all zeros. Unlike TraceID, the token can't be
+// recovered from it.
+func (token Token) SpanID() [8]byte {
+	d := traceDigest(token)
+	var id [8]byte
+	copy(id[:], d[4:12])
+	if id == [8]byte{} {
+		id[7] = 1
+	}
+	return id
+}
+
+// FromTraceID recovers the token of a trace id returned by Token.TraceID, it
+// reports false when the last 4 bytes don't match, e.g. for trace ids
+// generated by a tracer.
+func FromTraceID(id [16]byte) (Token, bool) {
+	var token Token
+	copy(token[:], id[:rawLen])
+	if binary.BigEndian.Uint32(id[rawLen:]) != tracePadding(token) {
+		return nilToken, false
+	}
+	return token, true
+}
+
+// tracePadding returns the last 4 bytes of the trace id of token, they are
+// never 0 so that the trace id of the nil token is valid.
+func tracePadding(token Token) uint32 {
+	d := traceDigest(token)
+	if p := binary.BigEndian.Uint32(d[:4]); p != 0 {
+		return p
+	}
+	return 1
+}
@@ -0,0 +1,52 @@
+package xtoken
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTraceID(t *testing.T) {
+	max := Token{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	for _, token := range []Token{nilToken, max, New(), IDs[0].token} {
+		id := token.TraceID()
+		if id != token.TraceID() {
+			t.Errorf("TraceID() of %x is not deterministic", token[:])
+		}
+		if id == [16]byte{} {
+			t.Errorf("TraceID() of %x is all zeros", token[:])
+		}
+		if !bytes.Equal(id[:rawLen], token[:]) {
+			t.Errorf("TraceID() = %x, want it to start with %x", id, token[:])
+		}
+		got, ok := FromTraceID(id)
+		if !ok || got != token {
+			t.Errorf("FromTraceID(%x) = %x, %v, want %x", id, got[:], ok, token[:])
+		}
+
+		span := token.SpanID()
+		if span != token.SpanID() {
+			t.Errorf("SpanID() of %x is not deterministic", token[:])
+		}
+		if span == [8]byte{} {
+			t.Errorf("SpanID() of %x is all zeros", token[:])
+		}
+	}
+	if a, b := New(), New(); a.SpanID() == b.SpanID() || a.TraceID() == b.TraceID() {
+		t.Error("different tokens have the same trace or span id")
+	}
+}
+
+func TestFromTraceIDForeign(t *testing.T) {
+	foreign := [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	if got, ok := FromTraceID(foreign); ok || !got.IsZero() {
+		t.Errorf("FromTraceID(%x) = %x, %v, want the nil token and false", foreign, got[:], ok)
+	}
+	id := New().TraceID()
+	for i := range id {
+		tampered := id
+		tampered[i] ^= 0x01
+		if _, ok := FromTraceID(tampered); ok {
+			t.Errorf("FromTraceID(%x) = true with byte %d flipped, want false", tampered, i)
+		}
+	}
+}
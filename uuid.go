@@ -0,0 +1,78 @@
+package xtoken
+
+import (
+	"encoding/hex"
+)
+
+const uuidEncodedLen = 36 // canonical UUID string len
+
+// UUID returns the token as a UUIDv8 (RFC 9562), for UUID columns and drivers.
+// The 12 bytes keep their order, around the version and variant bits:
+//
+//	tttttttt-mmmm-8gg0-80pp-ppcccccc0000
+//
+// with the timestamp t, the machine id m, the tag g, the pid p and the counter
+// c, so UUIDs sort like their tokens. The result
+// converts to the UUID types of most packages, e.g. uuid.UUID(token.UUID()).
+func (token Token) UUID() [16]byte {
+	var u [16]byte
+	copy(u[0:6], token[0:6])
+	u[6] = 0x80 | token[6]>>4
+	u[7] = token[6] << 4
+	u[8] = 0x80
+	copy(u[9:14], token[7:12])
+	return u
+}
+
+// UUIDString returns the canonical 36 chars string representation of the
+// UUID of the token, see Token.UUID.
+func (token Token) UUIDString() string {
+	u := token.UUID()
+	var text [uuidEncodedLen]byte
+	hex.Encode(text[0:8], u[0:4])
+	text[8] = '-'
+	hex.Encode(text[9:13], u[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:18], u[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:23], u[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:], u[10:])
+	return string(text[:])
+}
+
+// FromUUID reads a Token back from its UUID, see Token.UUID. It returns
+// ErrInvalidToken for UUIDs which weren't made from a token.
+func FromUUID(u [16]byte) (Token, error) {
+	if u[6]>>4 != 8 || u[7]&0x0F != 0 || u[8] != 0x80 || u[14] != 0 || u[15] != 0 {
+		return nilToken, ErrInvalidToken
+	}
+	var token Token
+	copy(token[0:6], u[0:6])
+	token[6] = u[6]<<4 | u[7]>>4
+	copy(token[7:12], u[9:14])
+	return token, nil
+}
+
+// FromUUIDString reads a Token from the canonical string representation of its
+// UUID, in lower or upper case.
+func FromUUIDString(s string) (Token, error) {
+	if len(s) != uuidEncodedLen {
+		return nilToken, &InvalidLengthError{Got: len(s), Want: uuidEncodedLen}
+	}
+	var u [16]byte
+	j := 0
+	for i := 0; i < len(s); i += 2 {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if s[i] != '-' {
+				return nilToken, &InvalidCharacterError{Char: s[i], Pos: i}
+			}
+			i++
+		}
+		if _, err := hex.Decode(u[j:j+1], []byte(s[i:i+2])); err != nil {
+			return nilToken, ErrInvalidToken
+		}
+		j++
+	}
+	return FromUUID(u)
+}
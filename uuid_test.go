@@ -0,0 +1,56 @@
+package xtoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	token := Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	if got, want := token.UUIDString(), "4d88e15b-60f4-8860-80e4-28412dc90000"; got != want {
+		t.Errorf("UUIDString() = %q, want %q", got, want)
+	}
+	for _, v := range append(IDs, IDParts{token: New()}) {
+		u := v.token.UUID()
+		if u[6]>>4 != 8 || u[8]>>6 != 2 {
+			t.Errorf("UUID() = %x, want version 8 and variant 10", u)
+		}
+		got, err := FromUUID(u)
+		if err != nil || got != v.token {
+			t.Errorf("FromUUID(%x) = %v, %v, want %v", u, got, err, v.token)
+		}
+		s := v.token.UUIDString()
+		for _, in := range []string{s, strings.ToUpper(s)} {
+			got, err = FromUUIDString(in)
+			if err != nil || got != v.token {
+				t.Errorf("FromUUIDString(%q) = %v, %v, want %v", in, got, err, v.token)
+			}
+		}
+	}
+}
+
+func TestUUIDOrder(t *testing.T) {
+	a, b := IDs[2].token, IDs[0].token
+	ua, ub := a.UUIDString(), b.UUIDString()
+	if (ua < ub) != a.Less(b) {
+		t.Errorf("UUIDs %s and %s don't sort like their tokens", ua, ub)
+	}
+}
+
+func TestFromUUIDStringInvalid(t *testing.T) {
+	s := New().UUIDString()
+	for _, in := range []string{
+		"",
+		s[1:],
+		strings.ReplaceAll(s, "-", "_"),
+		"g" + s[1:],
+		"123e4567-e89b-12d3-a456-426614174000", // UUIDv1
+		s[:34] + "01",                          // padding
+		s[:19] + "c" + s[20:],                  // variant
+	} {
+		if _, err := FromUUIDString(in); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromUUIDString(%q) err = %v, want ErrInvalidToken", in, err)
+		}
+	}
+}
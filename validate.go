@@ -0,0 +1,29 @@
+package xtoken
+
+// Validate returns nil if s is a well-formed string representation of a Token,
+// and the error FromString would return otherwise. It checks the length, the
+// alphabet and the order and padding chars without decoding nor allocating.
+func Validate(s string) error {
+	return validate(s)
+}
+
+// IsValid reports whether s is a well-formed string representation of a
+// Token, it's Validate(s) == nil.
+func IsValid(s string) bool {
+	return validate(s) == nil
+}
+
+// ValidateBytes is like Validate for a byte slice, it agrees with UnmarshalText.
+func ValidateBytes(b []byte) error {
+	return validate(b)
+}
+
+func validate[T string | []byte](text T) error {
+	if err := checkText(text, encodedLen, &StdEncoding.dec); err != nil {
+		return err
+	}
+	if !consistent(StdEncoding, text) {
+		return ErrInconsistentToken
+	}
+	return nil
+}
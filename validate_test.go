@@ -0,0 +1,101 @@
+package xtoken
+
+import (
+	"errors"
+	mathRand "math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, v := range IDs {
+		if err := Validate(v.token.String()); err != nil {
+			t.Errorf("Validate(%q) err: %v", v.token.String(), err)
+		}
+		if !IsValid(v.token.String()) {
+			t.Errorf("IsValid(%q) = false", v.token.String())
+		}
+	}
+	for _, s := range []string{"", "short", "________________________________", IDs[0].token.String()[1:] + "!"} {
+		if err := Validate(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Validate(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+		if IsValid(s) {
+			t.Errorf("IsValid(%q) = true", s)
+		}
+	}
+}
+
+func TestValidateAgreesWithUnmarshalText(t *testing.T) {
+	r := mathRand.New(mathRand.NewSource(1))
+	inputs := make([][]byte, 0, 100000)
+	for i := 0; i < 30000; i++ {
+		// Random strings over the alphabet, mostly inconsistent.
+		b := make([]byte, encodedLen)
+		for j := range b {
+			b[j] = encoding[r.Intn(len(encoding))]
+		}
+		inputs = append(inputs, b)
+	}
+	for i := 0; i < 30000; i++ {
+		// Valid strings with one char replaced by any byte.
+		b := []byte(New().String())
+		b[r.Intn(len(b))] = byte(r.Intn(256))
+		inputs = append(inputs, b)
+	}
+	for i := 0; i < 10000; i++ {
+		// Random bytes of random length.
+		b := make([]byte, r.Intn(2*encodedLen))
+		r.Read(b)
+		inputs = append(inputs, b)
+	}
+	valid := 0
+	for _, b := range inputs {
+		var token Token
+		want := token.UnmarshalText(b)
+		if got := ValidateBytes(b); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ValidateBytes(%q) = %v, UnmarshalText() = %v", b, got, want)
+		}
+		if got := Validate(string(b)); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Validate(%q) = %v, UnmarshalText() = %v", b, got, want)
+		}
+		if want == nil {
+			valid++
+		}
+	}
+	if valid == 0 {
+		t.Error("no valid inputs were generated")
+	}
+}
+
+func TestValidateAllocs(t *testing.T) {
+	s := New().String()
+	if n := testing.AllocsPerRun(100, func() { _ = Validate(s) }); n != 0 {
+		t.Errorf("Validate() allocs = %v, want 0", n)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	s := New().String()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Validate(s)
+	}
+}
+
+func BenchmarkFromString(b *testing.B) {
+	s := New().String()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = FromString(s)
+	}
+}
+
+func BenchmarkUnmarshalText(b *testing.B) {
+	text := []byte(New().String())
+	var token Token
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = token.UnmarshalText(text)
+	}
+}
@@ -0,0 +1,88 @@
+package xtoken
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNewWideWithTime(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	token, err := NewWideWithTime(now)
+	if err != nil {
+		t.Fatalf("NewWideWithTime() err: %v", err)
+	}
+	if got := token.Time(); !got.Equal(now) {
+		t.Errorf("Time() = %v, want %v", got, now)
+	}
+	if got, want := token.Machine(), *machineID.Load(); got != want {
+		t.Errorf("Machine() = %x, want %x", got, want)
+	}
+	if m := CurrentMachineID(); token.Machine()[0] != m[0] || token.Machine()[1] != m[1] {
+		t.Errorf("Machine() = %x, want the machine id %v of Token first", token.Machine(), m)
+	}
+	if other, _ := NewWideWithTime(now); other.Entropy() == token.Entropy() {
+		t.Errorf("Entropy() = %x twice, want random bytes", other.Entropy())
+	}
+
+	s := token.String()
+	if len(s) != wideEncodedLen {
+		t.Errorf("len(String()) = %d, want %d", len(s), wideEncodedLen)
+	}
+	got, err := FromStringWide(s)
+	if err != nil {
+		t.Fatalf("FromStringWide(%q) err: %v", s, err)
+	}
+	if got != token {
+		t.Errorf("FromStringWide(%q) = %x, want %x", s, got[:], token[:])
+	}
+}
+
+func TestTokenWideOrder(t *testing.T) {
+	base := time.UnixMilli(1700000000000)
+	var tokens []TokenWide
+	var strs []string
+	for _, offset := range []int{5, 0, 3, 1, 4, 2} {
+		token, err := NewWideWithTime(base.Add(time.Duration(offset) * time.Millisecond))
+		if err != nil {
+			t.Fatalf("NewWideWithTime() err: %v", err)
+		}
+		tokens = append(tokens, token)
+		strs = append(strs, token.String())
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Compare(tokens[j]) < 0 })
+	sort.Strings(strs)
+	for i := range tokens {
+		if got := tokens[i].Time(); !got.Equal(base.Add(time.Duration(i) * time.Millisecond)) {
+			t.Errorf("tokens[%d].Time() = %v, want in millisecond order", i, got)
+		}
+		if tokens[i].String() != strs[i] {
+			t.Errorf("strings don't sort like the raw tokens at %d", i)
+		}
+	}
+}
+
+func TestNewWideWithTimeBeforeEpoch(t *testing.T) {
+	for _, tt := range []time.Time{time.UnixMilli(-1), time.Unix(-1, 0), time.Time{}} {
+		var rangeErr *TimeOutOfRangeError
+		if _, err := NewWideWithTime(tt); !errors.Is(err, ErrTimeOutOfRange) || !errors.As(err, &rangeErr) {
+			t.Errorf("NewWideWithTime(%v) err = %v, want %v", tt, err, ErrTimeOutOfRange)
+		}
+	}
+	if token, err := NewWideWithTime(time.UnixMilli(0)); err != nil || token.UnixMilli() != 0 {
+		t.Errorf("NewWideWithTime() at the epoch = %v, %v", token.Time(), err)
+	}
+}
+
+func TestFromStringWideInvalid(t *testing.T) {
+	s := NewWide().String()
+	for _, in := range []string{"", s[1:], s + "0", "w" + s[1:], s[:wideEncodedLen-1] + "!"} {
+		if _, err := FromStringWide(in); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromStringWide(%q) err = %v, want ErrInvalidToken", in, err)
+		}
+	}
+	if !nilTokenWide.IsZero() || NewWide().IsZero() {
+		t.Error("IsZero() doesn't match the nil token")
+	}
+}
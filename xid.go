@@ -0,0 +1,28 @@
+package xtoken
+
+// The 12-byte layout of a Token is the one of rs/xid (and MongoDB ObjectId), and
+// its SortableString form is the xid string encoding, so both interoperate.
+
+// FromXIDString reads a Token from an rs/xid string: 20 chars of lowercase
+// base32hex (char set is 0-9, a-v).
+func FromXIDString(s string) (Token, error) {
+	return FromSortableString(s)
+}
+
+// XIDString returns the rs/xid string representation of the token, it's the
+// same as SortableString.
+func (token Token) XIDString() string {
+	return token.SortableString()
+}
+
+// FromXID converts the 12 raw bytes of an rs/xid ID, which share the layout of
+// a Token, e.g. FromXID(id) for an xid.ID. It's lossless.
+func FromXID(id [12]byte) Token {
+	return Token(id)
+}
+
+// XID returns the 12 raw bytes of the token as an rs/xid ID, e.g.
+// xid.ID(token.XID()). It's lossless.
+func (token Token) XID() [12]byte {
+	return token
+}
@@ -0,0 +1,48 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+// xidVectors were generated with github.com/rs/xid.
+var xidVectors = []struct {
+	token Token
+	xid   string
+}{
+	{Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}, "9m4e2mr0ui3e8a215n4g"},
+	{Token{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, "00000000000000000000"},
+	{Token{0x00, 0x00, 0x00, 0x00, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0x00, 0x00, 0x01}, "0000005anf6drrg0000g"},
+	{Token{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "vvvvvvvvvvvvvvvvvvvg"},
+	{Token{0x65, 0x4a, 0x1c, 0x30, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}, "cl51oc014d2mf2dbpnng"},
+}
+
+func TestXIDString(t *testing.T) {
+	for _, v := range xidVectors {
+		if got := v.token.XIDString(); got != v.xid {
+			t.Errorf("XIDString() = %q, want %q", got, v.xid)
+		}
+		got, err := FromXIDString(v.xid)
+		if err != nil {
+			t.Fatalf("FromXIDString(%q) err: %v", v.xid, err)
+		}
+		if got != v.token {
+			t.Errorf("FromXIDString(%q) = %x, want %x", v.xid, got[:], v.token[:])
+		}
+	}
+}
+
+func TestFromXIDStringInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"9m4e2mr0ui3e8a215n4",
+		"9m4e2mr0ui3e8a215n4gg",
+		"9m4e2mr0ui3e8a215n4z",
+		"9m4e2mr0ui3e8a215n-g",
+		IDs[0].token.String(),
+	} {
+		if _, err := FromXIDString(s); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("FromXIDString(%q) err = %v, want %v", s, err, ErrInvalidToken)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package xtokengrpc propagates request ids made of tokens through gRPC
+// servers and clients, like xtokenhttp does for HTTP: the server interceptors
+// read the request id metadata, replace it with a new token when it's missing
+// or malformed, store it in the context and send it back in the response
+// header. The client interceptors forward the request id of the context.
+//
+// Tokens are stored in the context with xtoken.NewContext, like xtokenhttp
+// does, so a request id crosses an HTTP gateway in front of a gRPC server:
+//
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(xtokengrpc.UnaryServerInterceptor()),
+//		grpc.ChainStreamInterceptor(xtokengrpc.StreamServerInterceptor()),
+//	)
+//	conn, err := grpc.NewClient(target,
+//		grpc.WithChainUnaryInterceptor(xtokengrpc.UnaryClientInterceptor()),
+//		grpc.WithChainStreamInterceptor(xtokengrpc.StreamClientInterceptor()),
+//	)
+package xtokengrpc
+
+import (
+	"context"
+
+	"github.com/zdz1715/xtoken"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultKey is the metadata key carrying the request id, gRPC keys are lowercase.
+const DefaultKey = "x-request-id"
+
+// config is the configuration of the interceptors and FromIncomingContext.
+type config struct {
+	key string
+}
+
+// Option configures the interceptors and FromIncomingContext.
+type Option func(c *config)
+
+// WithKey sets the metadata key carrying the request id, it defaults to DefaultKey.
+func WithKey(key string) Option {
+	return func(c *config) {
+		c.key = key
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{key: DefaultKey}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// NewContext returns a copy of ctx carrying token, it's xtoken.NewContext.
+func NewContext(ctx context.Context, token xtoken.Token) context.Context {
+	return xtoken.NewContext(ctx, token)
+}
+
+// FromContext returns the token carried by ctx, if any, it's xtoken.FromContext.
+func FromContext(ctx context.Context) (xtoken.Token, bool) {
+	return xtoken.FromContext(ctx)
+}
+
+// FromIncomingContext reads the token of the request id of the incoming
+// metadata of ctx, it reports false when it's missing or malformed.
+func FromIncomingContext(ctx context.Context, opts ...Option) (xtoken.Token, bool) {
+	_, token, ok := fromIncoming(ctx, newConfig(opts))
+	return token, ok
+}
+
+// fromIncoming returns the request id of the incoming metadata of ctx and its
+// token, the string is empty when it's missing.
+func fromIncoming(ctx context.Context, c config) (string, xtoken.Token, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(c.key)
+	if len(values) == 0 || values[0] == "" {
+		return "", xtoken.Token{}, false
+	}
+	token, err := xtoken.FromString(values[0])
+	return values[0], token, err == nil
+}
+
+// serverContext returns the context of a call carrying its request id, and
+// the request id to send back in the response header. A missing or malformed
+// inbound request id is replaced with a new token, in the incoming metadata as
+// well, rather than rejected.
+func serverContext(ctx context.Context, c config) (context.Context, string) {
+	s, token, ok := fromIncoming(ctx, c)
+	if !ok {
+		token = xtoken.New()
+		s = token.String()
+		md, _ := metadata.FromIncomingContext(ctx)
+		md = md.Copy()
+		md.Set(c.key, s)
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	return xtoken.NewContext(ctx, token), s
+}
+
+// UnaryServerInterceptor stores the request id of the unary calls in their
+// context, see FromContext, and sends it back in the response header.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts)
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, s := serverContext(ctx, c)
+		// The string representation isn't unique, echo the inbound one.
+		if err := grpc.SetHeader(ctx, metadata.Pairs(c.key, s)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor for streams.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts)
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, s := serverContext(ss.Context(), c)
+		if err := ss.SetHeader(metadata.Pairs(c.key, s)); err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// outgoingContext adds the request id of ctx to its outgoing metadata, unless
+// it's already set.
+func outgoingContext(ctx context.Context, c config) context.Context {
+	token, ok := xtoken.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(c.key)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, c.key, token.String())
+}
+
+// UnaryClientInterceptor forwards the request id of the context of the calls,
+// see NewContext, in their metadata. Calls without one are sent as is.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx, c), method, req, reply, cc, callOpts...)
+	}
+}
+
+// StreamClientInterceptor is like UnaryClientInterceptor for streams.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	c := newConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx, c), desc, cc, method, callOpts...)
+	}
+}
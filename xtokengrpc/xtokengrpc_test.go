@@ -0,0 +1,149 @@
+package xtokengrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// transportStream is a grpc.ServerTransportStream recording the header.
+type transportStream struct {
+	header metadata.MD
+}
+
+func (s *transportStream) Method() string { return "/test/Method" }
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *transportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *transportStream) SetTrailer(metadata.MD) error { return nil }
+
+// unary runs UnaryServerInterceptor with the incoming metadata md and returns
+// the context the handler saw and the response header.
+func unary(t *testing.T, md metadata.MD, opts ...Option) (context.Context, metadata.MD) {
+	t.Helper()
+	stream := &transportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), md), stream)
+	var got context.Context
+	_, err := UnaryServerInterceptor(opts...)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		got = ctx
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("UnaryServerInterceptor() err: %v", err)
+	}
+	return got, stream.header
+}
+
+func TestUnaryServerInterceptorPropagates(t *testing.T) {
+	want := xtoken.New()
+	s := want.String()
+	ctx, header := unary(t, metadata.Pairs(DefaultKey, s))
+	if got, ok := FromContext(ctx); !ok || got != want {
+		t.Errorf("FromContext() = %v, %v, want %v", got, ok, want)
+	}
+	if got, ok := FromIncomingContext(ctx); !ok || got != want {
+		t.Errorf("FromIncomingContext() = %v, %v, want %v", got, ok, want)
+	}
+	if got := header.Get(DefaultKey); len(got) != 1 || got[0] != s {
+		t.Errorf("response header = %q, want %q", got, s)
+	}
+}
+
+func TestUnaryServerInterceptorReplaces(t *testing.T) {
+	for _, md := range []metadata.MD{nil, metadata.Pairs(DefaultKey, ""), metadata.Pairs(DefaultKey, "garbage")} {
+		ctx, header := unary(t, md)
+		got, ok := FromContext(ctx)
+		if !ok || got.IsZero() {
+			t.Fatalf("FromContext() = %v, %v for %v, want a new token", got, ok, md)
+		}
+		if token, ok := FromIncomingContext(ctx); !ok || token != got {
+			t.Errorf("FromIncomingContext() = %v, %v, want %v", token, ok, got)
+		}
+		if token, err := xtoken.FromString(header.Get(DefaultKey)[0]); err != nil || token != got {
+			t.Errorf("response header = %v, %v, want %v", token, err, got)
+		}
+	}
+}
+
+func TestWithKey(t *testing.T) {
+	want := xtoken.New()
+	ctx, header := unary(t, metadata.Pairs("x-trace", want.String()), WithKey("x-trace"))
+	if got, ok := FromContext(ctx); !ok || got != want {
+		t.Errorf("FromContext() = %v, %v, want %v", got, ok, want)
+	}
+	if len(header.Get(DefaultKey)) != 0 || len(header.Get("x-trace")) != 1 {
+		t.Errorf("response header = %v, want x-trace only", header)
+	}
+}
+
+// dial starts a health server with the interceptors and returns a client
+// with them.
+func dial(t *testing.T) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() err: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestRoundTrip(t *testing.T) {
+	client := dial(t)
+	want := xtoken.New()
+	ctx := NewContext(context.Background(), want)
+
+	var header metadata.MD
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Check() err: %v", err)
+	}
+	if got, err := xtoken.FromString(header.Get(DefaultKey)[0]); err != nil || got != want {
+		t.Errorf("Check() response header = %v, %v, want %v", got, err, want)
+	}
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch() err: %v", err)
+	}
+	if header, err = stream.Header(); err != nil {
+		t.Fatalf("Header() err: %v", err)
+	}
+	if got, err := xtoken.FromString(header.Get(DefaultKey)[0]); err != nil || got != want {
+		t.Errorf("Watch() response header = %v, %v, want %v", got, err, want)
+	}
+
+	// Without a request id, the server generates one.
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Check() err: %v", err)
+	}
+	if got, err := xtoken.FromString(header.Get(DefaultKey)[0]); err != nil || got.IsZero() || got == want {
+		t.Errorf("Check() response header = %v, %v, want a new token", got, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package xtokenhttp propagates request ids made of tokens through HTTP
+// servers: Middleware reads the request id header, replaces it with a new
+// token when it's missing or malformed, stores it in the request context and
+// echoes it on the response.
+package xtokenhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zdz1715/xtoken"
+)
+
+// DefaultHeader is the header carrying the request id.
+const DefaultHeader = "X-Request-ID"
+
+// config is the configuration of Middleware and FromRequest.
+type config struct {
+	header   string
+	original bool
+}
+
+// Option configures Middleware and FromRequest.
+type Option func(c *config)
+
+// WithHeader sets the header carrying the request id, it defaults to DefaultHeader.
+func WithHeader(name string) Option {
+	return func(c *config) {
+		c.header = name
+	}
+}
+
+// WithOriginal makes Middleware keep the inbound header value it replaced in
+// the request context, see OriginalFromContext.
+func WithOriginal() Option {
+	return func(c *config) {
+		c.original = true
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{header: DefaultHeader}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+type contextKey int
+
+const originalKey contextKey = 0
+
+// NewContext returns a copy of ctx carrying token, it's xtoken.NewContext.
+func NewContext(ctx context.Context, token xtoken.Token) context.Context {
+	return xtoken.NewContext(ctx, token)
+}
+
+// FromContext returns the token carried by ctx, if any, it's xtoken.FromContext.
+func FromContext(ctx context.Context) (xtoken.Token, bool) {
+	return xtoken.FromContext(ctx)
+}
+
+// OriginalFromContext returns the malformed inbound header value Middleware
+// replaced, when it was configured WithOriginal.
+func OriginalFromContext(ctx context.Context) (string, bool) {
+	s, ok := ctx.Value(originalKey).(string)
+	return s, ok
+}
+
+// FromRequest reads the token of the request id header of r, it reports false
+// when the header is missing or malformed.
+func FromRequest(r *http.Request, opts ...Option) (xtoken.Token, bool) {
+	return fromRequest(r, newConfig(opts))
+}
+
+func fromRequest(r *http.Request, c config) (xtoken.Token, bool) {
+	s := r.Header.Get(c.header)
+	if s == "" {
+		return xtoken.Token{}, false
+	}
+	token, err := xtoken.FromString(s)
+	return token, err == nil
+}
+
+// Middleware stores the request id of the requests in their context, see
+// FromContext, and sets it on the responses. A missing or malformed inbound
+// request id is replaced with a new token, in the request headers as well,
+// rather than rejected.
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	c := newConfig(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		s := r.Header.Get(c.header)
+		token, ok := fromRequest(r, c)
+		if !ok {
+			if s != "" && c.original {
+				ctx = context.WithValue(ctx, originalKey, s)
+			}
+			token = xtoken.New()
+			s = token.String()
+		}
+		r = r.WithContext(NewContext(ctx, token))
+		if !ok {
+			// WithContext doesn't copy the headers.
+			h := r.Header.Clone()
+			if h == nil {
+				h = make(http.Header)
+			}
+			h.Set(c.header, s)
+			r.Header = h
+		}
+		// The string representation isn't unique, echo the inbound one.
+		w.Header().Set(c.header, s)
+		next.ServeHTTP(w, r)
+	})
+}
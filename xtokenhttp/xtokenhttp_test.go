@@ -0,0 +1,141 @@
+package xtokenhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+)
+
+// serve runs req through Middleware and returns the token and the header the
+// handler saw, and the response.
+func serve(t *testing.T, req *http.Request, opts ...Option) (xtoken.Token, *http.Request, *http.Response) {
+	t.Helper()
+	var got xtoken.Token
+	var gotReq *http.Request
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		if got, ok = FromContext(r.Context()); !ok {
+			t.Error("FromContext() = false, want a token")
+		}
+		gotReq = r
+	}), opts...)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return got, gotReq, rec.Result()
+}
+
+func TestMiddlewarePropagates(t *testing.T) {
+	want := xtoken.New()
+	header := want.String()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultHeader, header)
+	got, r, resp := serve(t, req)
+	if got != want {
+		t.Errorf("FromContext() = %v, want %v", got, want)
+	}
+	if token, ok := FromRequest(r); !ok || token != want {
+		t.Errorf("FromRequest() = %v, %v, want %v", token, ok, want)
+	}
+	if s := resp.Header.Get(DefaultHeader); s != header {
+		t.Errorf("response header = %q, want %q", s, header)
+	}
+}
+
+func TestMiddlewareReplaces(t *testing.T) {
+	for _, header := range []string{"", "garbage", xtoken.New().String()[1:]} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(DefaultHeader, header)
+		}
+		got, r, resp := serve(t, req, WithOriginal())
+		if got.IsZero() {
+			t.Fatalf("FromContext() = the nil token for header %q", header)
+		}
+		if token, ok := FromRequest(r); !ok || token != got {
+			t.Errorf("FromRequest() = %v, %v, want %v", token, ok, got)
+		}
+		if token, err := xtoken.FromString(resp.Header.Get(DefaultHeader)); err != nil || token != got {
+			t.Errorf("response header = %q, want %v", resp.Header.Get(DefaultHeader), got)
+		}
+		original, ok := OriginalFromContext(r.Context())
+		if header == "" && ok {
+			t.Errorf("OriginalFromContext() = %q, want none", original)
+		}
+		if header != "" && (!ok || original != header) {
+			t.Errorf("OriginalFromContext() = %q, %v, want %q", original, ok, header)
+		}
+		// The inbound request is left untouched.
+		if s := req.Header.Get(DefaultHeader); s != header {
+			t.Errorf("inbound header = %q, want %q", s, header)
+		}
+	}
+}
+
+func TestMiddlewareHeader(t *testing.T) {
+	want := xtoken.New()
+	header := want.String()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Trace", header)
+	req.Header.Set(DefaultHeader, xtoken.New().String())
+	got, _, resp := serve(t, req, WithHeader("X-Trace"))
+	if got != want {
+		t.Errorf("FromContext() = %v, want %v", got, want)
+	}
+	if s := resp.Header.Get("X-Trace"); s != header {
+		t.Errorf("response header = %q, want %q", s, header)
+	}
+	if _, ok := OriginalFromContext(context.Background()); ok {
+		t.Error("OriginalFromContext() = true, want false")
+	}
+}
+
+func TestMiddlewareConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[xtoken.Token]bool)
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := FromContext(r.Context())
+		mu.Lock()
+		defer mu.Unlock()
+		if seen[token] {
+			t.Errorf("token %v seen twice", token)
+		}
+		seen[token] = true
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 50 {
+		t.Errorf("%d distinct tokens, want 50", len(seen))
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext() = true on an empty context, want false")
+	}
+	want := xtoken.New()
+	if got, ok := FromContext(NewContext(context.Background(), want)); !ok || got != want {
+		t.Errorf("FromContext() = %v, %v, want %v", got, ok, want)
+	}
+	// The key is the one of the xtoken package.
+	if got, ok := xtoken.FromContext(NewContext(context.Background(), want)); !ok || got != want {
+		t.Errorf("xtoken.FromContext() = %v, %v, want %v", got, ok, want)
+	}
+}
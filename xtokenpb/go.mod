@@ -0,0 +1,10 @@
+module github.com/zdz1715/xtoken/xtokenpb
+
+go 1.22
+
+require (
+	github.com/zdz1715/xtoken v0.0.0
+	google.golang.org/protobuf v1.35.2
+)
+
+replace github.com/zdz1715/xtoken => ../
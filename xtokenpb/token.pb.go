@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: xtokenpb/token.proto
+
+package xtokenpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Token is an xtoken.Token, stored as its 12 raw bytes.
+type Token struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// value is the 12 raw bytes of the token, empty for the nil token.
+	Value []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_xtokenpb_token_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_xtokenpb_token_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_xtokenpb_token_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Token) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+var File_xtokenpb_token_proto protoreflect.FileDescriptor
+
+var file_xtokenpb_token_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x78, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x70, 0x62, 0x2f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x78, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x2e, 0x76,
+	0x31, 0x22, 0x1d, 0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a,
+	0x64, 0x7a, 0x31, 0x37, 0x31, 0x35, 0x2f, 0x78, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x2f, 0x78, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_xtokenpb_token_proto_rawDescOnce sync.Once
+	file_xtokenpb_token_proto_rawDescData = file_xtokenpb_token_proto_rawDesc
+)
+
+func file_xtokenpb_token_proto_rawDescGZIP() []byte {
+	file_xtokenpb_token_proto_rawDescOnce.Do(func() {
+		file_xtokenpb_token_proto_rawDescData = protoimpl.X.CompressGZIP(file_xtokenpb_token_proto_rawDescData)
+	})
+	return file_xtokenpb_token_proto_rawDescData
+}
+
+var file_xtokenpb_token_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_xtokenpb_token_proto_goTypes = []any{
+	(*Token)(nil), // 0: xtoken.v1.Token
+}
+var file_xtokenpb_token_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_xtokenpb_token_proto_init() }
+func file_xtokenpb_token_proto_init() {
+	if File_xtokenpb_token_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_xtokenpb_token_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_xtokenpb_token_proto_goTypes,
+		DependencyIndexes: file_xtokenpb_token_proto_depIdxs,
+		MessageInfos:      file_xtokenpb_token_proto_msgTypes,
+	}.Build()
+	File_xtokenpb_token_proto = out.File
+	file_xtokenpb_token_proto_rawDesc = nil
+	file_xtokenpb_token_proto_goTypes = nil
+	file_xtokenpb_token_proto_depIdxs = nil
+}
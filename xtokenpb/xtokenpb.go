@@ -0,0 +1,48 @@
+// Package xtokenpb defines the Token protobuf message, a token stored as its
+// 12 raw bytes, so that services exchanging tokens share a field shape and its
+// validation. Import xtokenpb/token.proto in the proto files:
+//
+//	import "xtokenpb/token.proto";
+//
+//	message GetUserRequest {
+//		xtoken.v1.Token id = 1;
+//	}
+//
+// and convert with ToProto and FromProto. The xtoken.Token type is also a
+// gogoproto custom type for plain bytes fields.
+package xtokenpb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative xtokenpb/token.proto
+
+import (
+	"github.com/zdz1715/xtoken"
+)
+
+// rawLen is the length of the raw bytes of a token.
+const rawLen = len(xtoken.Token{})
+
+// ToProto returns the message holding token, its value is empty for the nil token.
+func ToProto(token xtoken.Token) *Token {
+	if token.IsZero() {
+		return &Token{}
+	}
+	return &Token{Value: token.Bytes()}
+}
+
+// FromProto returns the token held by x, a nil message or an empty value is
+// the nil token. A value which isn't 12 bytes long returns an
+// *xtoken.InvalidLengthError.
+func FromProto(x *Token) (xtoken.Token, error) {
+	var token xtoken.Token
+	if len(x.GetValue()) == 0 {
+		return token, nil
+	}
+	err := token.UnmarshalBinary(x.GetValue())
+	return token, err
+}
+
+// CheckValid returns an error unless x holds a token, see FromProto.
+func (x *Token) CheckValid() error {
+	_, err := FromProto(x)
+	return err
+}
@@ -0,0 +1,78 @@
+package xtokenpb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zdz1715/xtoken"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, token := range []xtoken.Token{{}, xtoken.New()} {
+		b, err := proto.Marshal(ToProto(token))
+		if err != nil {
+			t.Fatalf("proto.Marshal() err: %v", err)
+		}
+		var x Token
+		if err := proto.Unmarshal(b, &x); err != nil {
+			t.Fatalf("proto.Unmarshal() err: %v", err)
+		}
+		if err := x.CheckValid(); err != nil {
+			t.Errorf("CheckValid() err: %v", err)
+		}
+		if got, err := FromProto(&x); err != nil || got != token {
+			t.Errorf("FromProto() = %v, %v, want %v", got, err, token)
+		}
+	}
+	if b, _ := proto.Marshal(ToProto(xtoken.Token{})); len(b) != 0 {
+		t.Errorf("proto.Marshal() of the nil token = %x, want empty", b)
+	}
+}
+
+func TestFromProto(t *testing.T) {
+	if got, err := FromProto(nil); err != nil || !got.IsZero() {
+		t.Errorf("FromProto(nil) = %v, %v, want the nil token", got, err)
+	}
+	x := &Token{Value: make([]byte, 16)}
+	_, err := FromProto(x)
+	var lengthErr *xtoken.InvalidLengthError
+	if !errors.As(err, &lengthErr) || lengthErr.Got != 16 || lengthErr.Want != rawLen {
+		t.Errorf("FromProto() err = %v, want an *InvalidLengthError", err)
+	}
+	if err := x.CheckValid(); !errors.Is(err, xtoken.ErrInvalidToken) {
+		t.Errorf("CheckValid() err = %v, want %v", err, xtoken.ErrInvalidToken)
+	}
+}
+
+// TestWireCompatible checks that the message and the gogoproto custom type
+// methods of xtoken.Token write the same bytes field.
+func TestWireCompatible(t *testing.T) {
+	token := xtoken.New()
+	b, err := proto.Marshal(ToProto(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, err := token.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tag 1 with the length-delimited wire type, then the length.
+	want := append([]byte{1<<3 | 2, byte(len(field))}, field...)
+	if string(b) != string(want) {
+		t.Errorf("proto.Marshal() = %x, want %x", b, want)
+	}
+
+	j, err := protojson.Marshal(ToProto(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x Token
+	if err := protojson.Unmarshal(j, &x); err != nil {
+		t.Fatalf("protojson.Unmarshal(%s) err: %v", j, err)
+	}
+	if got, err := FromProto(&x); err != nil || got != token {
+		t.Errorf("FromProto() after JSON = %v, %v, want %v", got, err, token)
+	}
+}
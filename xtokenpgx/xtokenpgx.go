@@ -0,0 +1,163 @@
+// Package xtokenpgx registers xtoken.Token with pgx, so tokens are stored in
+// bytea columns as their 12 raw bytes and in text columns as their canonical
+// string representation, rather than through the string of Token.Value:
+//
+//	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//		xtokenpgx.Register(conn.TypeMap())
+//		return nil
+//	}
+//
+// A domain made for tokens, e.g. CREATE DOMAIN token AS bytea, is registered
+// with RegisterType.
+package xtokenpgx
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/zdz1715/xtoken"
+)
+
+// Codec is a pgtype.Codec encoding and scanning xtoken.Token and
+// *xtoken.Token values in the columns of Base, a pgtype.ByteaCodec or a
+// pgtype.TextCodec. Other values and DecodeValue are handled by Base, so the
+// column keeps accepting its usual Go types.
+type Codec struct {
+	Base pgtype.Codec
+}
+
+// FormatSupported implements pgtype.Codec.
+func (c *Codec) FormatSupported(format int16) bool {
+	return c.Base.FormatSupported(format)
+}
+
+// PreferredFormat implements pgtype.Codec.
+func (c *Codec) PreferredFormat() int16 {
+	return c.Base.PreferredFormat()
+}
+
+// binary reports whether tokens are stored as their raw bytes.
+func (c *Codec) binary() bool {
+	switch c.Base.(type) {
+	case pgtype.ByteaCodec, *pgtype.ByteaCodec:
+		return true
+	}
+	return false
+}
+
+// PlanEncode implements pgtype.Codec.
+func (c *Codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	switch value.(type) {
+	case xtoken.Token, *xtoken.Token:
+		return encodePlan{binary: c.binary(), format: format}
+	}
+	return c.Base.PlanEncode(m, oid, format, value)
+}
+
+// PlanScan implements pgtype.Codec.
+func (c *Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(*xtoken.Token); ok {
+		return scanPlan{binary: c.binary(), format: format}
+	}
+	return c.Base.PlanScan(m, oid, format, target)
+}
+
+// DecodeDatabaseSQLValue implements pgtype.Codec.
+func (c *Codec) DecodeDatabaseSQLValue(m *pgtype.Map, oid uint32, format int16, src []byte) (driver.Value, error) {
+	return c.Base.DecodeDatabaseSQLValue(m, oid, format, src)
+}
+
+// DecodeValue implements pgtype.Codec.
+func (c *Codec) DecodeValue(m *pgtype.Map, oid uint32, format int16, src []byte) (any, error) {
+	return c.Base.DecodeValue(m, oid, format, src)
+}
+
+// encodePlan encodes tokens, the nil token is NULL like with Token.Value.
+type encodePlan struct {
+	binary bool
+	format int16
+}
+
+func (p encodePlan) Encode(value any, buf []byte) ([]byte, error) {
+	var token xtoken.Token
+	switch v := value.(type) {
+	case xtoken.Token:
+		token = v
+	case *xtoken.Token:
+		if v == nil {
+			return nil, nil
+		}
+		token = *v
+	}
+	if token.IsZero() {
+		return nil, nil
+	}
+	switch {
+	case !p.binary:
+		return append(buf, token.CanonicalString()...), nil
+	case p.format == pgtype.BinaryFormatCode:
+		return append(buf, token[:]...), nil
+	}
+	// The text format of bytea is hex with a \x prefix.
+	buf = append(buf, `\x`...)
+	return hex.AppendEncode(buf, token[:]), nil
+}
+
+// scanPlan scans tokens, NULL is the nil token like with Token.Scan.
+type scanPlan struct {
+	binary bool
+	format int16
+}
+
+func (p scanPlan) Scan(src []byte, target any) error {
+	token := target.(*xtoken.Token)
+	if src == nil {
+		*token = xtoken.Token{}
+		return nil
+	}
+	if p.binary && p.format == pgtype.TextFormatCode {
+		if len(src) < 2 || src[0] != '\\' || src[1] != 'x' {
+			return fmt.Errorf("xtokenpgx: invalid bytea text %q", src)
+		}
+		var err error
+		if src, err = hex.DecodeString(string(src[2:])); err != nil {
+			return fmt.Errorf("xtokenpgx: invalid bytea text: %w", err)
+		}
+	}
+	// src is only valid during the call, Scan copies it.
+	return token.Scan(src)
+}
+
+// Register makes the bytea, text, varchar and bpchar types of m encode and
+// scan tokens with Codec.
+func Register(m *pgtype.Map) {
+	for _, name := range []string{"bytea", "text", "varchar", "bpchar"} {
+		if t, ok := m.TypeForName(name); ok {
+			registerType(m, t)
+		}
+	}
+}
+
+// RegisterType loads the type name of the database of conn, a domain over
+// bytea or text such as CREATE DOMAIN token AS bytea, and registers it with
+// Codec in the type map of conn.
+func RegisterType(ctx context.Context, conn *pgx.Conn, name string) error {
+	t, err := conn.LoadType(ctx, name)
+	if err != nil {
+		return err
+	}
+	registerType(conn.TypeMap(), t)
+	return nil
+}
+
+// registerType registers t in m with Codec wrapping its codec.
+func registerType(m *pgtype.Map, t *pgtype.Type) {
+	if _, ok := t.Codec.(*Codec); ok {
+		return
+	}
+	m.RegisterType(&pgtype.Type{Name: t.Name, OID: t.OID, Codec: &Codec{Base: t.Codec}})
+}
@@ -0,0 +1,107 @@
+package xtokenpgx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/zdz1715/xtoken"
+)
+
+var token = xtoken.Token{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+
+func newMap() *pgtype.Map {
+	m := pgtype.NewMap()
+	Register(m)
+	return m
+}
+
+func TestEncodeScan(t *testing.T) {
+	m := newMap()
+	tests := []struct {
+		name   string
+		oid    uint32
+		format int16
+		want   string
+	}{
+		{"bytea binary", pgtype.ByteaOID, pgtype.BinaryFormatCode, string(token[:])},
+		{"bytea text", pgtype.ByteaOID, pgtype.TextFormatCode, `\x4d88e15b60f486e428412dc9`},
+		{"text", pgtype.TextOID, pgtype.TextFormatCode, token.CanonicalString()},
+		{"varchar binary", pgtype.VarcharOID, pgtype.BinaryFormatCode, token.CanonicalString()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, value := range []any{token, &token} {
+				buf, err := m.Encode(tt.oid, tt.format, value, nil)
+				if err != nil {
+					t.Fatalf("Encode(%T) err: %v", value, err)
+				}
+				if string(buf) != tt.want {
+					t.Errorf("Encode(%T) = %q, want %q", value, buf, tt.want)
+				}
+			}
+			var got xtoken.Token
+			if err := m.Scan(tt.oid, tt.format, []byte(tt.want), &got); err != nil {
+				t.Fatalf("Scan() err: %v", err)
+			}
+			if got != token {
+				t.Errorf("Scan() = %v, want %v", got, token)
+			}
+		})
+	}
+}
+
+func TestNull(t *testing.T) {
+	m := newMap()
+	for _, value := range []any{xtoken.Token{}, (*xtoken.Token)(nil)} {
+		if buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, value, nil); err != nil || buf != nil {
+			t.Errorf("Encode(%#v) = %q, %v, want NULL", value, buf, err)
+		}
+	}
+	got := token
+	if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, nil, &got); err != nil || !got.IsZero() {
+		t.Errorf("Scan(NULL) = %v, %v, want the nil token", got, err)
+	}
+}
+
+func TestOtherValues(t *testing.T) {
+	m := newMap()
+	// The columns keep their usual Go types.
+	buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, []byte{1, 2, 3}, nil)
+	if err != nil || !bytes.Equal(buf, []byte{1, 2, 3}) {
+		t.Errorf("Encode([]byte) = %v, %v", buf, err)
+	}
+	var s string
+	if err := m.Scan(pgtype.TextOID, pgtype.TextFormatCode, []byte("hello"), &s); err != nil || s != "hello" {
+		t.Errorf("Scan(string) = %q, %v", s, err)
+	}
+	if v, err := m.TypeForOID(pgtype.ByteaOID); !err || v.Codec.(*Codec).Base != (pgtype.ByteaCodec{}) {
+		t.Errorf("bytea codec = %#v, want a Codec of ByteaCodec", v.Codec)
+	}
+}
+
+func TestScanInvalid(t *testing.T) {
+	m := newMap()
+	var got xtoken.Token
+	for _, src := range []string{`4d88e15b60f486e428412dc9`, `\xzz`, `\x4d88`} {
+		if err := m.Scan(pgtype.ByteaOID, pgtype.TextFormatCode, []byte(src), &got); err == nil {
+			t.Errorf("Scan(%q) err = nil", src)
+		}
+	}
+}
+
+func TestDomain(t *testing.T) {
+	m := pgtype.NewMap()
+	// As returned by conn.LoadType for CREATE DOMAIN token AS bytea.
+	registerType(m, &pgtype.Type{Name: "token", OID: 100000, Codec: pgtype.ByteaCodec{}})
+	buf, err := m.Encode(100000, pgtype.BinaryFormatCode, token, nil)
+	if err != nil || !bytes.Equal(buf, token[:]) {
+		t.Errorf("Encode() = %x, %v, want %x", buf, err, token[:])
+	}
+	// Registering twice doesn't wrap the Codec again.
+	typ, _ := m.TypeForOID(100000)
+	registerType(m, typ)
+	if typ, _ := m.TypeForOID(100000); typ.Codec.(*Codec).Base != (pgtype.ByteaCodec{}) {
+		t.Errorf("codec = %#v, want a Codec of ByteaCodec", typ.Codec)
+	}
+}
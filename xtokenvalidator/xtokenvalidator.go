@@ -0,0 +1,62 @@
+// Package xtokenvalidator registers the "xtoken" tag with
+// github.com/go-playground/validator, so that request fields holding tokens
+// are checked declaratively:
+//
+//	validate := validator.New()
+//	if err := xtokenvalidator.Register(validate); err != nil {
+//		return err
+//	}
+//
+//	type GetUserRequest struct {
+//		ID     string  `json:"id" validate:"required,xtoken"`
+//		Parent *string `json:"parent" validate:"omitempty,xtoken"`
+//		Ref    string  `json:"ref" validate:"xtoken=any"`
+//	}
+//
+// Without a parameter, string and []byte fields must be the String
+// representation of a token, as checked by xtoken.Validate. With xtoken=any
+// they may be in any format read by xtoken.ParseAny: the String
+// representation, hex or, for []byte, the 12 raw bytes. xtoken.Token fields
+// must not be the nil token.
+package xtokenvalidator
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/zdz1715/xtoken"
+)
+
+// Tag is the validation tag registered by Register.
+const Tag = "xtoken"
+
+var tokenType = reflect.TypeOf(xtoken.Token{})
+
+// Register registers the Tag validation with v.
+func Register(v *validator.Validate) error {
+	return v.RegisterValidation(Tag, Valid)
+}
+
+// Valid is the validator.Func of Tag, it can be registered under another tag
+// with v.RegisterValidation.
+func Valid(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	anyFormat := fl.Param() == "any"
+	switch {
+	case field.Type() == tokenType:
+		return !field.Interface().(xtoken.Token).IsZero()
+	case field.Kind() == reflect.String:
+		if anyFormat {
+			_, err := xtoken.ParseAny(field.String())
+			return err == nil
+		}
+		return xtoken.IsValid(field.String())
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8:
+		if anyFormat {
+			_, err := xtoken.ParseAny(field.Bytes())
+			return err == nil
+		}
+		return xtoken.ValidateBytes(field.Bytes()) == nil
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package xtokenvalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/zdz1715/xtoken"
+)
+
+type request struct {
+	ID     string        `validate:"required,xtoken"`
+	Parent *string       `validate:"omitempty,xtoken"`
+	Ref    string        `validate:"omitempty,xtoken=any"`
+	Raw    []byte        `validate:"omitempty,xtoken=any"`
+	Token  *xtoken.Token `validate:"omitempty,xtoken"`
+}
+
+func TestRegister(t *testing.T) {
+	v := validator.New()
+	if err := Register(v); err != nil {
+		t.Fatalf("Register() err: %v", err)
+	}
+	token := xtoken.New()
+	s, hex := token.String(), token.Hex()
+	bad := s[1:] + "!"
+	nilToken := xtoken.Token{}
+	tests := []struct {
+		name  string
+		req   request
+		field string // the failing field, if any
+	}{
+		{"valid", request{ID: s, Parent: &s, Ref: hex, Raw: token.Bytes(), Token: &token}, ""},
+		{"string ref", request{ID: s, Ref: s, Raw: []byte(hex)}, ""},
+		{"missing id", request{}, "ID"},
+		{"invalid id", request{ID: bad}, "ID"},
+		{"hex id", request{ID: hex}, "ID"},
+		{"invalid parent", request{ID: s, Parent: &bad}, "Parent"},
+		{"invalid ref", request{ID: s, Ref: bad}, "Ref"},
+		{"invalid raw", request{ID: s, Raw: token.Bytes()[1:]}, "Raw"},
+		{"nil token", request{ID: s, Token: &nilToken}, "Token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.req)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("Struct() err: %v", err)
+				}
+				return
+			}
+			var errs validator.ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field() != tt.field {
+				t.Fatalf("Struct() err = %v, want a failure of %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestVar(t *testing.T) {
+	v := validator.New()
+	if err := v.RegisterValidation("id", Valid); err != nil {
+		t.Fatalf("RegisterValidation() err: %v", err)
+	}
+	if err := v.Var(xtoken.New().String(), "id"); err != nil {
+		t.Errorf("Var() err: %v", err)
+	}
+	if err := v.Var(42, "id"); err == nil {
+		t.Error("Var() of an int err = nil")
+	}
+}
@@ -0,0 +1,24 @@
+package xtoken
+
+// MarshalYAML implements yaml.Marshaler of gopkg.in/yaml.v2 and gopkg.in/yaml.v3,
+// the token is serialized as its string representation.
+func (token Token) MarshalYAML() (interface{}, error) {
+	return token.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler of gopkg.in/yaml.v2, which
+// gopkg.in/yaml.v3 supports as well. It accepts a scalar string representation
+// of a token, an empty scalar is the nil token. YAML decoders don't call
+// unmarshalers for null, which leaves the token untouched, that is the nil
+// token when decoding into a new value.
+func (token *Token) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	if s == "" {
+		*token = nilToken
+		return nil
+	}
+	return token.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,56 @@
+package xtoken
+
+import (
+	"errors"
+	"testing"
+)
+
+// yamlScalar returns an unmarshal function as a YAML decoder would pass it for
+// a scalar node holding v.
+func yamlScalar(v interface{}) func(interface{}) error {
+	return func(out interface{}) error {
+		s, ok := out.(*string)
+		if !ok {
+			return errors.New("unsupported type")
+		}
+		str, ok := v.(string)
+		if !ok {
+			return errors.New("cannot unmarshal non-string into string")
+		}
+		*s = str
+		return nil
+	}
+}
+
+func TestYAML(t *testing.T) {
+	for _, v := range IDs {
+		out, err := v.token.MarshalYAML()
+		if err != nil {
+			t.Fatalf("MarshalYAML() err: %v", err)
+		}
+		s, ok := out.(string)
+		if !ok {
+			t.Fatalf("MarshalYAML() = %T, want a string", out)
+		}
+		var got Token
+		if err := got.UnmarshalYAML(yamlScalar(s)); err != nil {
+			t.Fatalf("UnmarshalYAML(%q) err: %v", s, err)
+		}
+		if got != v.token {
+			t.Errorf("UnmarshalYAML(%q) = %v, want %v", s, got, v.token)
+		}
+	}
+}
+
+func TestUnmarshalYAMLInvalid(t *testing.T) {
+	token := New()
+	if err := token.UnmarshalYAML(yamlScalar("")); err != nil || !token.IsZero() {
+		t.Errorf("UnmarshalYAML(\"\") = %v, %v, want the nil token", token, err)
+	}
+	if err := token.UnmarshalYAML(yamlScalar("not a token")); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("UnmarshalYAML() err = %v, want %v", err, ErrInvalidToken)
+	}
+	if err := token.UnmarshalYAML(yamlScalar(42)); err == nil {
+		t.Errorf("UnmarshalYAML() of a non-string err = nil, want an error")
+	}
+}